@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+	"github.com/HybriStratus/test-github-groups/http"
+	"github.com/HybriStratus/test-github-groups/http/auth"
+	"github.com/HybriStratus/test-github-groups/http/plan"
+)
+
+// options are the flags accepted by every command
+type options struct {
+	org    string
+	apiURL string
+	output string
+	dryRun bool
+	debug  bool
+
+	tokenFile      string
+	tokenCommand   string
+	appID          int64
+	installationID int64
+	appKeyFile     string
+
+	plan *plan.Client
+	// result buffers the result of a command in JSON dry-run mode, see resultWriter
+	result bytes.Buffer
+}
+
+// newFlagSet creates the flag set of a command with the shared options registered
+func newFlagSet(name string) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	opts := &options{}
+	fs.StringVar(&opts.org, "org", os.Getenv("GITHUB_ORG"), "GitHub organization")
+	fs.StringVar(&opts.apiURL, "api-url", os.Getenv("GITHUB_API_URL"), "GitHub API root")
+	fs.StringVar(&opts.output, "output", "table", "Output format, table or json")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "Print the changes instead of making them")
+	fs.BoolVar(&opts.debug, "debug", false, "Log every GitHub API request to stderr")
+	fs.StringVar(&opts.tokenFile, "token-file", os.Getenv("AUTH_TOKEN_FILE"), "File holding the token")
+	fs.StringVar(&opts.tokenCommand, "token-command", os.Getenv("AUTH_TOKEN_COMMAND"), "Command printing the token")
+	fs.Int64Var(&opts.appID, "app-id", envInt64("GITHUB_APP_ID"), "GitHub App ID")
+	fs.Int64Var(&opts.installationID, "installation-id", envInt64("GITHUB_APP_INSTALLATION_ID"), "GitHub App installation ID")
+	fs.StringVar(&opts.appKeyFile, "app-key-file", os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"), "GitHub App private key PEM file")
+	return fs, opts
+}
+
+// parseArgs parses flags placed before, between or after the positional arguments and
+// checks the shared options, it returns the positional arguments
+func parseArgs(fs *flag.FlagSet, opts *options, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if opts.org == "" {
+		return nil, fmt.Errorf("--org or $GITHUB_ORG is required")
+	}
+	if opts.output != "table" && opts.output != "json" {
+		return nil, fmt.Errorf("--output must be table or json, got %q", opts.output)
+	}
+	return positional, nil
+}
+
+// service builds the groups.Service for the options, planning the changes in dry-run mode
+// and logging the requests to stderr in debug mode
+func (opts *options) service(stderr io.Writer) (*groups.Service, error) {
+	client := newHTTPClient()
+	source, err := opts.tokenSource(client)
+	if err != nil {
+		return nil, err
+	}
+	if opts.dryRun {
+		opts.plan = plan.NewClient(client)
+		client = opts.plan
+	}
+
+	service := groups.NewService(client, opts.org, opts.apiURL)
+	if source != nil {
+		service.TokenSource = source
+	}
+	if opts.debug {
+		service.Logger = &writerLogger{w: stderr}
+	}
+	return service, nil
+}
+
+// writerLogger writes the messages of the service as lines of the level, the message and
+// its key=value fields
+type writerLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *writerLogger) Debug(msg string, args ...interface{}) { l.log("DEBUG", msg, args) }
+func (l *writerLogger) Info(msg string, args ...interface{})  { l.log("INFO", msg, args) }
+func (l *writerLogger) Warn(msg string, args ...interface{})  { l.log("WARN", msg, args) }
+func (l *writerLogger) Error(msg string, args ...interface{}) { l.log("ERROR", msg, args) }
+
+// log writes a single line, the lines of the concurrent requests of bulk commands do not interleave
+func (l *writerLogger) log(level, msg string, args []interface{}) {
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s %s", level, msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&line, " %v=%v", args[i], args[i+1])
+	}
+	line.WriteString("\n")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.w, line.String())
+}
+
+// tokenSource picks the credentials given by the options, nil keeps reading $AUTH_TOKEN
+func (opts *options) tokenSource(client http.Client) (auth.TokenSource, error) {
+	switch {
+	case opts.appID != 0:
+		if opts.installationID == 0 || opts.appKeyFile == "" {
+			return nil, fmt.Errorf("--app-id requires --installation-id and --app-key-file")
+		}
+		key, err := ioutil.ReadFile(opts.appKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error in reading App private key %s", err.Error())
+		}
+		apiURL := opts.apiURL
+		if apiURL == "" {
+			apiURL = groups.DefaultAPIURL
+		}
+		return auth.NewAppTokenSource(client, apiURL, opts.appID, opts.installationID, key)
+	case opts.tokenFile != "":
+		return auth.NewFileTokenSource(opts.tokenFile), nil
+	case strings.TrimSpace(opts.tokenCommand) != "":
+		command := strings.Fields(opts.tokenCommand)
+		return auth.NewCommandTokenSource(command[0], command[1:]...), nil
+	}
+	return nil, nil
+}
+
+// envInt64 reads an integer environment variable, zero when unset or malformed
+func envInt64(name string) int64 {
+	value, _ := strconv.ParseInt(os.Getenv(name), 10, 64)
+	return value
+}
+
+// resultWriter returns where the result of a command is written. In JSON dry-run mode it
+// is buffered so writePlan can write it along with the plan as a single JSON document
+func (opts *options) resultWriter(stdout io.Writer) io.Writer {
+	if opts.plan == nil || opts.output != "json" {
+		return stdout
+	}
+	return &opts.result
+}
+
+// writePlan writes the planned changes in dry-run mode, after the result in table output
+// and as {"result": ..., "plan": {"changes": [...]}} in JSON output
+func (opts *options) writePlan(stdout io.Writer) error {
+	if opts.plan == nil {
+		return nil
+	}
+	if opts.output != "json" {
+		return opts.plan.WriteText(stdout)
+	}
+
+	result := json.RawMessage(bytes.TrimSpace(opts.result.Bytes()))
+	if len(result) == 0 {
+		result = json.RawMessage("null")
+	}
+	type planned struct {
+		Changes []plan.Change `json:"changes"`
+	}
+	return writeOutput(stdout, opts.output, struct {
+		Result json.RawMessage `json:"result"`
+		Plan   planned         `json:"plan"`
+	}{Result: result, Plan: planned{Changes: opts.plan.Changes()}}, nil)
+}
+
+// stringsFlag collects the values of a flag given several times
+type stringsFlag []string
+
+// String returns the values joined by commas
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set adds a value
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// checkArgs verifies the number of positional arguments of a command
+func checkArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return fmt.Errorf("expected arguments <%s>, got %d", strings.Join(names, "> <"), len(args))
+	}
+	return nil
+}
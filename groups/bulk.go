@@ -0,0 +1,237 @@
+package groups
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http"
+)
+
+// Statuses of the users of a bulk membership operation
+const (
+	StatusOK            = "ok"
+	StatusAlreadyMember = "already-member"
+	StatusNotFound      = "not-found"
+	StatusError         = "error"
+)
+
+// DefaultWorkers is the number of concurrent requests of the bulk operations
+const DefaultWorkers = 4
+
+// bulkRateLimitAttempts is the number of times a user is tried while GitHub rate limits it
+const bulkRateLimitAttempts = 3
+
+// BulkOptions configures the bulk membership operations
+type BulkOptions struct {
+	// Workers is the number of requests sent at once, DefaultWorkers when 0
+	Workers int
+	// UpdateRole lets AddMembers change the role of users already in the team through
+	// SetMemberRole, they are otherwise left with the role they have
+	UpdateRole bool
+}
+
+// MemberResult is the outcome of a bulk operation for a single user
+type MemberResult struct {
+	User   string `json:"user"`
+	Status string `json:"status"`
+	Role   string `json:"role,omitempty"`
+	State  string `json:"state,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Err is the error behind StatusError and StatusNotFound
+	Err error `json:"-"`
+}
+
+// BulkReport lists the result of every user of a bulk operation, in the order they were given
+type BulkReport struct {
+	Team    string         `json:"team"`
+	Results []MemberResult `json:"results"`
+}
+
+// Failed returns the results of the users the operation failed for
+func (report *BulkReport) Failed() []MemberResult {
+	var failed []MemberResult
+	for _, result := range report.Results {
+		if result.Status == StatusNotFound || result.Status == StatusError {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// String renders the report with one user per line
+func (report *BulkReport) String() string {
+	var buf bytes.Buffer
+	for _, result := range report.Results {
+		fmt.Fprintf(&buf, "%s %s", result.User, result.Status)
+		if result.Role != "" {
+			fmt.Fprintf(&buf, " %s", result.Role)
+		}
+		if result.Error != "" {
+			fmt.Fprintf(&buf, " (%s)", result.Error)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// AddMembers adds users to the GitHub team with role, sending the requests from a pool of
+// workers. Users already in the team, active or pending, are reported as already members
+// with the role they have unless opts.UpdateRole is set, and a failure for one user does
+// not stop the others. The error is only set when the team members can not be listed
+func (s *Service) AddMembers(ctx context.Context, teamName string, users []string, role string, opts *BulkOptions) (*BulkReport, error) {
+	if role == "" {
+		role = DefaultRoleType
+	}
+	current, err := s.teamMemberships(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	updateRole := opts != nil && opts.UpdateRole
+
+	report := s.runBulk(ctx, teamName, users, opts, func(user string) MemberResult {
+		membership, ok := current[strings.ToLower(user)]
+		if !ok {
+			added, err := s.AddMemeberToTeam(ctx, teamName, user, role)
+			if err != nil {
+				return failedResult(err)
+			}
+			return MemberResult{Status: StatusOK, Role: added.Role, State: added.State}
+		}
+		if updateRole && membership.Role == "" {
+			// The role of pending users is not listed
+			pending, err := s.GetMembership(ctx, teamName, user)
+			if err != nil {
+				return failedResult(err)
+			}
+			membership = *pending
+		}
+		if !updateRole || membership.Role == role {
+			return MemberResult{Status: StatusAlreadyMember, Role: membership.Role, State: membership.State}
+		}
+		changed, err := s.SetMemberRole(ctx, teamName, user, role)
+		if err != nil {
+			return failedResult(err)
+		}
+		return MemberResult{Status: StatusOK, Role: changed.Role, State: changed.State}
+	})
+	return report, nil
+}
+
+// teamMemberships returns the memberships of the GitHub team by lowercase login. Active
+// members have their role, pending ones invited to the organization through the team
+// have none since GitHub does not list it
+func (s *Service) teamMemberships(ctx context.Context, teamName string) (map[string]Membership, error) {
+	members, err := s.ListMemebersOfTeamByRole(ctx, teamName, RoleAll, nil)
+	if err != nil {
+		return nil, err
+	}
+	maintainers, err := s.ListMemebersOfTeamByRole(ctx, teamName, RoleMaintainer, nil)
+	if err != nil {
+		return nil, err
+	}
+	invitations, err := s.ListTeamInvitations(ctx, teamName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]Membership{}
+	for _, member := range members {
+		current[strings.ToLower(member.Login)] = Membership{Role: RoleMember, State: StateActive}
+	}
+	for _, maintainer := range maintainers {
+		current[strings.ToLower(maintainer.Login)] = Membership{Role: RoleMaintainer, State: StateActive}
+	}
+	for _, invitation := range invitations {
+		// Invitations sent by email have no login until they are accepted
+		if _, ok := current[strings.ToLower(invitation.Login)]; !ok && invitation.Login != "" {
+			current[strings.ToLower(invitation.Login)] = Membership{State: StatePending}
+		}
+	}
+	return current, nil
+}
+
+// RemoveMembers removes users from the GitHub team, sending the requests from a pool of
+// workers. A failure for one user does not stop the others
+func (s *Service) RemoveMembers(ctx context.Context, teamName string, users []string, opts *BulkOptions) *BulkReport {
+	return s.runBulk(ctx, teamName, users, opts, func(user string) MemberResult {
+		err := s.DeleteMemberFromTeam(ctx, teamName, user)
+		if err != nil {
+			return failedResult(err)
+		}
+		return MemberResult{Status: StatusOK}
+	})
+}
+
+// failedResult reports err as the result of a user
+func failedResult(err error) MemberResult {
+	status := StatusError
+	if IsNotFound(err) {
+		status = StatusNotFound
+	}
+	return MemberResult{Status: status, Error: err.Error(), Err: err}
+}
+
+// runBulk calls do for every user from a pool of workers. Once GitHub rate limits a
+// request every worker holds its requests until the limit resets, then the rate-limited
+// user is tried again, up to bulkRateLimitAttempts times
+func (s *Service) runBulk(ctx context.Context, teamName string, users []string, opts *BulkOptions, do func(user string) MemberResult) *BulkReport {
+	workers := DefaultWorkers
+	if opts != nil && opts.Workers > 0 {
+		workers = opts.Workers
+	}
+	sleep := s.sleep
+	if sleep == nil {
+		sleep = http.Sleep
+	}
+
+	report := &BulkReport{Team: teamName, Results: make([]MemberResult, len(users))}
+	indexes := make(chan int)
+	var mu sync.Mutex
+	// resume is the time the requests are held until after a rate limit
+	var resume time.Time
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var result MemberResult
+				for attempt := 1; ; attempt++ {
+					mu.Lock()
+					wait := time.Until(resume)
+					mu.Unlock()
+					err := ctx.Err()
+					if err == nil && wait > 0 {
+						err = sleep(ctx, wait)
+					}
+					if err != nil {
+						result = failedResult(err)
+						break
+					}
+
+					result = do(users[i])
+					if !IsRateLimited(result.Err) || attempt == bulkRateLimitAttempts {
+						break
+					}
+					mu.Lock()
+					if until := time.Now().Add(rateLimitWait(result.Err)); until.After(resume) {
+						resume = until
+					}
+					mu.Unlock()
+				}
+				result.User = users[i]
+				report.Results[i] = result
+			}
+		}()
+	}
+	for i := range users {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return report
+}
@@ -0,0 +1,223 @@
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestAddMembers tests AddMembers function of a team
+func TestAddMembers(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "test_team")
+	membership := func(user string, response http.Response) responses {
+		return responses{method: http.MethodPut, url: teamURL + "/memberships/" + user, response: response}
+	}
+	ok := func() http.Response {
+		return http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "active"}`))}
+	}
+	retryAfter := http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Retry-After": []string{"30"}}}
+	reset := strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10)
+	exhausted := http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"X-Ratelimit-Remaining": []string{"0"}, "X-Ratelimit-Reset": []string{reset}}}
+	// Create your table test
+	tests := []struct {
+		name                string
+		users               []string
+		workers             int
+		updateRole          bool
+		requestClients      []responses
+		expectedStatus      []string
+		expectedFailed      int
+		expectedRateLimited []string
+		expectedWait        time.Duration
+		expected            error
+		maxInFlight         int
+	}{
+		{
+			name:    "Testing mixed results",
+			users:   []string{"alice", "bob", "ghost", "carol"},
+			workers: 2,
+			requestClients: []responses{
+				membership("bob", http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "active"}`))}),
+				membership("ghost", http.Response{StatusCode: http.StatusNotFound}),
+				membership("carol", http.Response{StatusCode: http.StatusBadGateway}),
+			},
+			expectedStatus: []string{StatusAlreadyMember, StatusOK, StatusNotFound, StatusError},
+			expectedFailed: 2,
+			maxInFlight:    2,
+		},
+		{
+			name:    "Testing wait after rate limit",
+			users:   []string{"bob", "carol", "dave"},
+			workers: 1,
+			requestClients: []responses{
+				membership("bob", retryAfter),
+				membership("bob", ok()),
+				membership("carol", ok()),
+				membership("dave", ok()),
+			},
+			expectedStatus: []string{StatusOK, StatusOK, StatusOK},
+			expectedWait:   30 * time.Second,
+			maxInFlight:    1,
+		},
+		{
+			name:    "Testing rate limit outlasting the attempts",
+			users:   []string{"bob", "carol"},
+			workers: 1,
+			requestClients: []responses{
+				membership("bob", exhausted),
+				membership("bob", exhausted),
+				membership("bob", exhausted),
+				membership("carol", ok()),
+			},
+			expectedStatus:      []string{StatusError, StatusOK},
+			expectedFailed:      1,
+			expectedRateLimited: []string{"bob"},
+			expectedWait:        11 * time.Second,
+			maxInFlight:         1,
+		},
+		{
+			name:           "Testing maintainers and pending users left alone",
+			users:          []string{"mallory", "pat"},
+			workers:        1,
+			expectedStatus: []string{StatusAlreadyMember, StatusAlreadyMember},
+			maxInFlight:    1,
+		},
+		{
+			name:       "Testing role update",
+			users:      []string{"mallory", "pat"},
+			workers:    1,
+			updateRole: true,
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL + "/memberships/mallory",
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"role": "maintainer", "state": "active"}`))},
+				},
+				membership("mallory", ok()),
+				{
+					method:   http.MethodGet,
+					url:      teamURL + "/memberships/pat",
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "pending"}`))},
+				},
+			},
+			expectedStatus: []string{StatusOK, StatusAlreadyMember},
+			maxInFlight:    1,
+		},
+		{
+			name:     "Testing missing team",
+			users:    []string{"bob"},
+			expected: fmt.Errorf("Error in getting all members of a team : test_team: 404 Not Found"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			if tt.expected == nil {
+				// Alice is a member, Mallory a maintainer and Pat is pending
+				mockClient.SetResponses(http.MethodGet, teamURL+"/members?role=all", http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "Alice"}, {"login": "Mallory"}]`)),
+				})
+				mockClient.SetResponses(http.MethodGet, teamURL+"/members?role=maintainer", http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "Mallory"}]`)),
+				})
+				mockClient.SetResponses(http.MethodGet, teamURL+"/invitations", http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ConvertBytesToIoReadCloser([]byte(`[{"id": 1, "login": "Pat"}]`)),
+				})
+			} else {
+				mockClient.SetResponses(http.MethodGet, teamURL+"/members?role=all", http.Response{StatusCode: http.StatusNotFound})
+			}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+			// Give the other workers the time to send their requests
+			mockClient.SetLatency(5 * time.Millisecond)
+
+			var waits []time.Duration
+			service := NewService(mockClient, TestOrg, "")
+			service.sleep = func(ctx context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+			report, got := service.AddMembers(context.Background(), "test_team", tt.users, RoleMember, &BulkOptions{Workers: tt.workers, UpdateRole: tt.updateRole})
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Fatalf("wanted %v, got %v", tt.expected, got)
+			}
+
+			var status, rateLimited []string
+			for i, result := range report.Results {
+				if result.User != tt.users[i] {
+					t.Errorf("wanted user %s at %d, got %s", tt.users[i], i, result.User)
+				}
+				status = append(status, result.Status)
+				if IsRateLimited(result.Err) {
+					rateLimited = append(rateLimited, result.User)
+				}
+			}
+			if !reflect.DeepEqual(rateLimited, tt.expectedRateLimited) {
+				t.Errorf("wanted %v rate limited, got %v", tt.expectedRateLimited, rateLimited)
+			}
+			// The wait is computed from the clock, it may be a little shorter
+			if tt.expectedWait == 0 && len(waits) > 0 {
+				t.Errorf("wanted no wait, got %v", waits)
+			}
+			if tt.expectedWait > 0 && (len(waits) == 0 || waits[0] > tt.expectedWait || waits[0] < tt.expectedWait-2*time.Second) {
+				t.Errorf("wanted a wait of %v, got %v", tt.expectedWait, waits)
+			}
+			if !reflect.DeepEqual(status, tt.expectedStatus) {
+				t.Errorf("wanted %v, got %v", tt.expectedStatus, status)
+			}
+			if len(report.Failed()) != tt.expectedFailed {
+				t.Errorf("wanted %d failures, got %d", tt.expectedFailed, len(report.Failed()))
+			}
+			if inFlight := mockClient.MaxInFlight(); inFlight > tt.maxInFlight {
+				t.Errorf("wanted at most %d requests in flight, got %d", tt.maxInFlight, inFlight)
+			}
+		})
+	}
+}
+
+// TestRemoveMembers tests RemoveMembers function of a team and the rendering of its report
+func TestRemoveMembers(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "test_team")
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodDelete, teamURL+"/memberships/alice", http.Response{StatusCode: http.StatusNoContent})
+	mockClient.SetResponses(http.MethodDelete, teamURL+"/memberships/ghost", http.Response{StatusCode: http.StatusNotFound})
+
+	service := NewService(mockClient, TestOrg, "")
+	report := service.RemoveMembers(context.Background(), "test_team", []string{"alice", "ghost"}, nil)
+
+	expectedText := "alice ok\nghost not-found (Error in deleting ghost from team test_team: 404 Not Found)\n"
+	if report.String() != expectedText {
+		t.Errorf("wanted %q, got %q", expectedText, report.String())
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON := `{"team":"test_team","results":[{"user":"alice","status":"ok"},` +
+		`{"user":"ghost","status":"not-found","error":"Error in deleting ghost from team test_team: 404 Not Found"}]}`
+	if string(data) != expectedJSON {
+		t.Errorf("wanted %s, got %s", expectedJSON, data)
+	}
+}
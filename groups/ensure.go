@@ -0,0 +1,84 @@
+package groups
+
+import (
+	"context"
+)
+
+// Actions taken by EnsureTeam
+const (
+	TeamCreated   = "created"
+	TeamUpdated   = "updated"
+	TeamUnchanged = "unchanged"
+)
+
+// EnsureResult reports what EnsureTeam did to a team
+type EnsureResult struct {
+	// Action is TeamCreated, TeamUpdated or TeamUnchanged
+	Action string `json:"action"`
+	// Fields lists the fields that were patched by an update
+	Fields []string `json:"fields,omitempty"`
+}
+
+// EnsureTeam creates team when it does not exist yet, otherwise it patches the description,
+// privacy and parent that differ from team. Empty fields are left as they are, Maintainers
+// and Repos are only used on creation. Calling it again with the same team changes nothing
+func (s *Service) EnsureTeam(ctx context.Context, team *Team) (*TeamDetails, *EnsureResult, error) {
+	details, result, err := s.ensureTeam(ctx, team)
+	if err == nil && details.Slug != "" {
+		team.Slug = details.Slug
+	}
+	return details, result, err
+}
+
+// ensureTeam makes the changes of EnsureTeam
+func (s *Service) ensureTeam(ctx context.Context, team *Team) (*TeamDetails, *EnsureResult, error) {
+	team, err := s.resolveParent(ctx, team)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lookup := team.Slug
+	if lookup == "" {
+		lookup = team.Name
+	}
+	details, err := s.GetTeamDetails(ctx, lookup)
+	if IsNotFound(err) {
+		details, err = s.CreateTeam(ctx, team)
+		if err != nil {
+			return nil, nil, err
+		}
+		return details, &EnsureResult{Action: TeamCreated}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	update := &Team{Name: details.Name, Slug: details.Slug}
+	result := &EnsureResult{Action: TeamUnchanged}
+	if team.Description != "" && team.Description != details.Description {
+		update.Description = team.Description
+		result.Fields = append(result.Fields, "description")
+	}
+	if team.Privacy != "" && team.Privacy != details.Privacy {
+		update.Privacy = team.Privacy
+		result.Fields = append(result.Fields, "privacy")
+	}
+	if team.ParentTeamID != 0 && (details.Parent == nil || details.Parent.ID != team.ParentTeamID) {
+		update.ParentTeamID = team.ParentTeamID
+		result.Fields = append(result.Fields, "parent")
+	}
+	if len(result.Fields) == 0 {
+		return details, result, nil
+	}
+
+	updated, err := s.UpdateTeam(ctx, update)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Action = TeamUpdated
+	// Keep the ID of the existing team in case the response is sparse
+	if updated.ID == 0 {
+		updated.ID = details.ID
+	}
+	return updated, result, nil
+}
@@ -0,0 +1,125 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestEnsureTeam tests EnsureTeam function of a team
+func TestEnsureTeam(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "platform-team")
+	existing := `{"id": 5, "name": "Platform Team", "slug": "platform-team", "description": "old", "privacy": "closed", "parent": {"id": 1}}`
+	// Create your table test
+	tests := []struct {
+		name           string
+		team           Team
+		requestClients []responses
+		expectedResult EnsureResult
+		expectedBody   string
+		expected       error
+	}{
+		{
+			name: "Testing creation of a missing team",
+			team: Team{Name: "Platform Team", Description: "new"},
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusNotFound},
+				},
+			},
+			expectedResult: EnsureResult{Action: TeamCreated},
+			expectedBody:   `POST {"name":"Platform Team","description":"new"}`,
+		},
+		{
+			name: "Testing update of the differing fields",
+			team: Team{Name: "Platform Team", Description: "new", Privacy: "closed", ParentTeamID: 2},
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(existing))},
+				},
+			},
+			expectedResult: EnsureResult{Action: TeamUpdated, Fields: []string{"description", "parent"}},
+			expectedBody:   `PATCH {"name":"Platform Team","description":"new","parent_team_id":2}`,
+		},
+		{
+			name: "Testing unchanged team",
+			team: Team{Name: "platform-team", Description: "old", ParentTeamID: 1},
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(existing))},
+				},
+			},
+			expectedResult: EnsureResult{Action: TeamUnchanged},
+		},
+		{
+			name: "Testing failure of the lookup",
+			team: Team{Name: "Platform Team"},
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusForbidden},
+				},
+			},
+			expected: fmt.Errorf("Error in getting team deatils : Platform Team: 403 Forbidden"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client and answer the changes with the body they send
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+			var body string
+			service := NewService(clientFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet {
+					return mockClient.Do(req)
+				}
+				data, _ := ioutil.ReadAll(req.Body)
+				body = req.Method + " " + string(data)
+				return &http.Response{
+					StatusCode: map[string]int{http.MethodPost: http.StatusCreated, http.MethodPatch: http.StatusOK}[req.Method],
+					Body:       ConvertBytesToIoReadCloser([]byte(`{"name": "Platform Team", "slug": "platform-team"}`)),
+				}, nil
+			}), TestOrg, "")
+
+			details, result, got := service.EnsureTeam(context.Background(), &tt.team)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Fatalf("wanted %v, got %v", tt.expected, got.Error())
+			}
+			if !reflect.DeepEqual(*result, tt.expectedResult) {
+				t.Errorf("wanted %v, got %v", tt.expectedResult, *result)
+			}
+			if body != tt.expectedBody {
+				t.Errorf("wanted body %s, got %s", tt.expectedBody, body)
+			}
+			if tt.team.Slug != "platform-team" {
+				t.Errorf("wanted slug platform-team, got %s", tt.team.Slug)
+			}
+			if result.Action == TeamUpdated && details.ID != 5 {
+				t.Errorf("wanted the ID of the existing team, got %d", details.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package groups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	h "net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError is a field-level validation error reported by GitHub
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message,omitempty"`
+}
+
+// APIError is returned when GitHub answers a request with an unexpected status code
+type APIError struct {
+	// Op describes the operation that failed
+	Op               string       `json:"-"`
+	StatusCode       int          `json:"-"`
+	Method           string       `json:"-"`
+	URL              string       `json:"-"`
+	RequestID        string       `json:"-"`
+	Message          string       `json:"message"`
+	Errors           []FieldError `json:"errors"`
+	DocumentationURL string       `json:"documentation_url"`
+
+	rateLimited bool
+	// retryAfter and reset are the Retry-After and X-RateLimit-Reset of a rate-limited request
+	retryAfter time.Duration
+	reset      time.Time
+}
+
+// newAPIError builds an APIError for op from the response and the GitHub error body
+func newAPIError(op string, response *h.Response) *APIError {
+	apiErr := &APIError{
+		Op:         op,
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get("X-GitHub-Request-Id"),
+	}
+	if response.Request != nil {
+		apiErr.Method = response.Request.Method
+		apiErr.URL = response.Request.URL.String()
+	}
+	if response.Body != nil {
+		defer response.Body.Close()
+		// The body is only informational, a missing or malformed one leaves the fields empty
+		if body, err := ioutil.ReadAll(response.Body); err == nil {
+			json.Unmarshal(body, apiErr)
+		}
+	}
+
+	switch response.StatusCode {
+	case h.StatusTooManyRequests:
+		apiErr.rateLimited = true
+	case h.StatusForbidden:
+		apiErr.rateLimited = response.Header.Get("Retry-After") != "" ||
+			response.Header.Get("X-RateLimit-Remaining") == "0" ||
+			strings.Contains(strings.ToLower(apiErr.Message), "rate limit")
+	}
+	if apiErr.rateLimited {
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			apiErr.retryAfter = time.Duration(seconds) * time.Second
+		}
+		if reset, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			apiErr.reset = time.Unix(reset, 0)
+		}
+	}
+	return apiErr
+}
+
+// Error returns the failed operation with the status and message reported by GitHub
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = h.StatusText(e.StatusCode)
+	}
+	errString := fmt.Sprintf("%s: %d %s", e.Op, e.StatusCode, message)
+	for _, fieldErr := range e.Errors {
+		if fieldErr.Message != "" {
+			errString += fmt.Sprintf("; %s", fieldErr.Message)
+		} else {
+			errString += fmt.Sprintf("; %s %s %s", fieldErr.Resource, fieldErr.Field, fieldErr.Code)
+		}
+	}
+	return errString
+}
+
+// hasStatus tells whether err is an APIError with statusCode
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound tells whether err is an APIError for a missing resource
+func IsNotFound(err error) bool {
+	return hasStatus(err, h.StatusNotFound)
+}
+
+// IsConflict tells whether err is an APIError for a conflicting change
+func IsConflict(err error) bool {
+	return hasStatus(err, h.StatusConflict)
+}
+
+// IsUnauthorized tells whether err is an APIError for missing or bad credentials
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, h.StatusUnauthorized)
+}
+
+// IsForbidden tells whether err is an APIError for a request the credentials are
+// not allowed to make, rate-limited requests are reported by IsRateLimited instead
+func IsForbidden(err error) bool {
+	return hasStatus(err, h.StatusForbidden) && !IsRateLimited(err)
+}
+
+// IsValidationFailed tells whether err is an APIError for an invalid request payload
+func IsValidationFailed(err error) bool {
+	return hasStatus(err, h.StatusUnprocessableEntity)
+}
+
+// IsAlreadyExists tells whether err is an APIError for creating a resource that
+// already exists, such as a team with a taken name
+func IsAlreadyExists(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != h.StatusUnprocessableEntity {
+		return false
+	}
+	for _, fieldErr := range apiErr.Errors {
+		if fieldErr.Code == "already_exists" || strings.Contains(fieldErr.Message, "already exists") {
+			return true
+		}
+	}
+	return strings.Contains(apiErr.Message, "already exists")
+}
+
+// rateLimitBackoff is the wait after a rate limit when GitHub did not tell how long to wait
+const rateLimitBackoff = time.Minute
+
+// rateLimitWait returns how long to wait before retrying the request err was rate limited
+// for, from its Retry-After or else its X-RateLimit-Reset
+func rateLimitWait(err error) time.Duration {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return rateLimitBackoff
+	}
+	if apiErr.retryAfter > 0 {
+		return apiErr.retryAfter
+	}
+	if !apiErr.reset.IsZero() {
+		// The reset has a precision of a second, the wait is rounded up
+		return time.Until(apiErr.reset) + time.Second
+	}
+	return rateLimitBackoff
+}
+
+// IsRateLimited tells whether err is an APIError for an exceeded primary or
+// secondary rate limit
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.rateLimited
+}
@@ -0,0 +1,106 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestAPIError tests that failed calls return an APIError with the GitHub error body
+func TestAPIError(t *testing.T) {
+
+	url := fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg)
+	// Create your table test
+	tests := []struct {
+		name          string
+		response      http.Response
+		expected      string
+		notFound      bool
+		alreadyExists bool
+		rateLimited   bool
+		forbidden     bool
+	}{
+		{
+			name: "Testing team that already exists",
+			response: http.Response{
+				StatusCode: http.StatusUnprocessableEntity,
+				Header:     http.Header{"X-Github-Request-Id": {"C0DE:1234"}},
+				Body: ConvertBytesToIoReadCloser([]byte(`{
+    "message": "Validation Failed",
+    "errors": [{"resource": "Team", "code": "already_exists", "field": "name"}],
+    "documentation_url": "https://docs.github.com/rest/teams/teams#create-a-team"
+}`)),
+			},
+			expected:      "Error in creating a new team : test_team: 422 Validation Failed; Team name already_exists",
+			alreadyExists: true,
+		},
+		{
+			name: "Testing missing organization",
+			response: http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"message": "Not Found"}`)),
+			},
+			expected: "Error in creating a new team : test_team: 404 Not Found",
+			notFound: true,
+		},
+		{
+			name: "Testing secondary rate limit",
+			response: http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"Retry-After": {"60"}},
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"message": "You have exceeded a secondary rate limit."}`)),
+			},
+			expected:    "Error in creating a new team : test_team: 403 You have exceeded a secondary rate limit.",
+			rateLimited: true,
+		},
+		{
+			name: "Testing missing permissions",
+			response: http.Response{
+				StatusCode: http.StatusForbidden,
+			},
+			expected:  "Error in creating a new team : test_team: 403 Forbidden",
+			forbidden: true,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodPost, url, tt.response)
+
+			service := NewService(mockClient, TestOrg, "")
+			_, got := service.CreateTeam(context.Background(), &Team{Name: "test_team"})
+			if got == nil || got.Error() != tt.expected {
+				t.Fatalf("wanted %v, got %v", tt.expected, got)
+			}
+
+			apiErr, ok := got.(*APIError)
+			if !ok {
+				t.Fatalf("wanted *APIError, got %T", got)
+			}
+			if apiErr.Method != http.MethodPost || apiErr.URL != url {
+				t.Errorf("wanted POST %s, got %s %s", url, apiErr.Method, apiErr.URL)
+			}
+			if IsNotFound(got) != tt.notFound {
+				t.Errorf("wanted IsNotFound %v", tt.notFound)
+			}
+			if IsAlreadyExists(got) != tt.alreadyExists {
+				t.Errorf("wanted IsAlreadyExists %v", tt.alreadyExists)
+			}
+			if IsRateLimited(got) != tt.rateLimited {
+				t.Errorf("wanted IsRateLimited %v", tt.rateLimited)
+			}
+			if IsForbidden(got) != tt.forbidden {
+				t.Errorf("wanted IsForbidden %v", tt.forbidden)
+			}
+			if IsNotFound(fmt.Errorf("wrapped: %w", got)) != tt.notFound {
+				t.Errorf("wanted wrapped IsNotFound %v", tt.notFound)
+			}
+		})
+	}
+}
@@ -2,214 +2,306 @@ package groups
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	h "net/http"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/HybriStratus/test-github-groups/http"
+	"github.com/HybriStratus/test-github-groups/http/auth"
 )
 
-const baseURL = "https://api.github.com/orgs"
+// DefaultAPIURL is the root of the public GitHub REST API
+const DefaultAPIURL = "https://api.github.com"
+
+// TestOrg is the organization used by the tests of this module
 const TestOrg = "HybriStratus"
 const DefaultRoleType = "member"
 
+// mediaType is the media type of the GitHub REST API v3
+const mediaType = "application/vnd.github.v3+json"
+
+// DefaultTokenEnv is the environment variable the token is read from by default
+const DefaultTokenEnv = "AUTH_TOKEN"
+
+// Service performs team operations against a single GitHub organization
+type Service struct {
+	Client http.Client
+	Org    string
+	APIURL string
+	// TokenSource provides the token of every request, requests are sent
+	// unauthenticated when it is nil or returns an empty token
+	TokenSource auth.TokenSource
+	// Logger records the method, URL, status, duration and request ID of every request
+	// at debug level, nothing is logged when it is nil
+	Logger Logger
+
+	// sleep waits for a rate limit to reset in the bulk operations, http.Sleep when nil
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewService creates a Service for org. An empty apiURL defaults to DefaultAPIURL,
+// GitHub Enterprise Server hosts are reached through https://<host>/api/v3.
+// The token is read from DefaultTokenEnv until TokenSource is replaced
+func NewService(client http.Client, org, apiURL string) *Service {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Service{
+		Client:      client,
+		Org:         org,
+		APIURL:      strings.TrimSuffix(apiURL, "/"),
+		TokenSource: auth.EnvToken(DefaultTokenEnv),
+	}
+}
+
+// orgURL builds the URL of path under the organization of the service
+func (s *Service) orgURL(path string, a ...interface{}) string {
+	return fmt.Sprintf("%s/orgs/%s", s.APIURL, s.Org) + fmt.Sprintf(path, a...)
+}
+
 type Team struct {
-	Name         string   `json:"name"`
+	Name         string   `json:"name,omitempty"`
 	Description  string   `json:"description,omitempty"`
 	Maintainers  []string `json:"maintainers,omitempty"`
 	Repos        []string `json:"repo_names,omitempty"`
 	Privacy      string   `json:"privacy,omitempty"`
 	ParentTeamID int      `json:"parent_team_id,omitempty"`
+	// ParentTeamSlug names the parent team, its ID is looked up when ParentTeamID is not set
+	ParentTeamSlug string `json:"-"`
+	// Slug addresses the team on GitHub, it is derived from Name when empty and filled in
+	// from the responses of CreateTeam and UpdateTeam. With a Slug UpdateTeam sends Name as
+	// well, so a Slug kept across a change of Name renames the team
+	Slug string `json:"-"`
+}
+
+// path returns the escaped URL path segment of the team
+func (team *Team) path() string {
+	if team.Slug != "" {
+		return pathSegment(team.Slug)
+	}
+	return teamPath(team.Name)
+}
+
+// sendsName tells whether UpdateTeam sends Name, which is when Slug addresses the team.
+// Name is then the name the team should have, even when only its case changes
+func (team *Team) sendsName() bool {
+	return team.Slug != "" && team.Name != ""
 }
 
-func sendHTTPRequest(client http.Client, method string, url string, body io.Reader) (response *h.Response, err error) {
+func (s *Service) sendHTTPRequest(ctx context.Context, method string, url string, body io.Reader) (response *h.Response, err error) {
+	return s.sendHTTPRequestWithAccept(ctx, method, url, mediaType, body)
+}
+
+// sendHTTPRequestWithAccept sends the request asking for the accept media type, which
+// selects an alternative representation on some endpoints
+func (s *Service) sendHTTPRequestWithAccept(ctx context.Context, method string, url string, accept string, body io.Reader) (response *h.Response, err error) {
 	req, err := h.NewRequest(method, url, body)
 	if err != nil {
 		err = fmt.Errorf("Error occurred while creating http request " + err.Error())
 		return
 	}
-
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("AUTH_TOKEN"))
-	req.Header.Set("Content-Type", "application/vnd.github.v3+json")
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	// Cancelling ctx aborts the request in every client of the chain
+	req = req.WithContext(ctx)
+
+	if s.TokenSource != nil {
+		token, err := s.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Error in getting auth token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("Accept", accept)
 	// Make the API call
-	response, err = client.Do(req)
+	start := time.Now()
+	response, err = s.Client.Do(req)
 	if err != nil {
-		err = fmt.Errorf("Error occurred while calling github API: " + err.Error())
+		s.logger().Debug("github request failed", "method", method, "url", url, "duration", time.Since(start), "error", err)
+		err = fmt.Errorf("Error occurred while calling github API: %w", err)
 		return
 	}
+	if response.Request == nil {
+		response.Request = req
+	}
+	s.logger().Debug("github request", "method", method, "url", url, "status", response.StatusCode,
+		"duration", time.Since(start), "request_id", response.Header.Get("X-GitHub-Request-Id"))
+	return
+}
+
+// readJSON reads the API response body and unmarshals it into v
+func readJSON(response *h.Response, v interface{}) (err error) {
+	defer response.Body.Close()
+	// Read the bytes from the response body
+	responseBodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("Error in reading response from API response %s", err.Error())
+	}
+
+	// Convert the bytes into the response model
+	err = json.Unmarshal(responseBodyBytes, v)
+	if err != nil {
+		return fmt.Errorf("Error in unmarshalling response from API response %s", err.Error())
+	}
 	return
 }
 
 // CreateTeam creates team in Github
-func (team *Team) CreateTeam(client http.Client) (err error) {
+func (s *Service) CreateTeam(ctx context.Context, team *Team) (details *TeamDetails, err error) {
 
-	url := fmt.Sprintf("%s/%s/teams", baseURL, TestOrg)
+	url := s.orgURL("/teams")
+	payload, err := s.resolveParent(ctx, team)
+	if err != nil {
+		return
+	}
 	// Convert the json body object to bytes
-	jsonValue, err := json.Marshal(team)
+	jsonValue, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("Error in marshalling the request payload")
+		return nil, fmt.Errorf("Error in marshalling the request payload")
 	}
 
-	response, err := sendHTTPRequest(client, "POST", url, bytes.NewBuffer(jsonValue))
-	fmt.Printf("Return status code of the request: %d\n", response.StatusCode)
+	response, err := s.sendHTTPRequest(ctx, "POST", url, bytes.NewBuffer(jsonValue))
 	if err != nil {
 		return
 	}
 	if response.StatusCode != h.StatusCreated {
-		err = fmt.Errorf("Error in creating a new team : %s", team.Name)
-		return
-	}
-	defer response.Body.Close()
-	// Read the bytes from the response body
-	responseBodyBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("Error in reading response from API response%s", err.Error())
+		err = newAPIError(fmt.Sprintf("Error in creating a new team : %s", team.Name), response)
 		return
 	}
 
-	var teamResponse interface{}
-	// Convert the bytes in interface
-	err = json.Unmarshal(responseBodyBytes, &teamResponse)
+	details = &TeamDetails{}
+	err = readJSON(response, details)
 	if err != nil {
-		err = fmt.Errorf("Error in unmarshalling response from API response %s", err.Error())
-		return
+		return nil, err
+	}
+	if details.Slug != "" {
+		team.Slug = details.Slug
 	}
-	fmt.Printf("Response: %v\n\n", teamResponse)
 	return
 }
 
 // GetTeamDetails gets GitHub team details
-func (team *Team) GetTeamDetails(client http.Client) (err error) {
+func (s *Service) GetTeamDetails(ctx context.Context, teamName string) (details *TeamDetails, err error) {
 
-	url := fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, team.Name)
+	url := s.orgURL("/teams/%s", teamPath(teamName))
 
-	response, err := sendHTTPRequest(client, "GET", url, nil)
-	fmt.Printf("Return status code of the request: %d\n", response.StatusCode)
+	response, err := s.sendHTTPRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return
 	}
 
 	if response.StatusCode != h.StatusOK {
-		err = fmt.Errorf("Error in getting team deatils : %s", team.Name)
-		return
-	}
-	defer response.Body.Close()
-	// Read the bytes from the response body
-	responseBodyBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("Error in reading response from API response%s", err.Error())
+		err = newAPIError(fmt.Sprintf("Error in getting team deatils : %s", teamName), response)
 		return
 	}
 
-	var teamResponse interface{}
-	// Convert the bytes in interface
-	err = json.Unmarshal(responseBodyBytes, &teamResponse)
+	details = &TeamDetails{}
+	err = readJSON(response, details)
 	if err != nil {
-		err = fmt.Errorf("Error in unmarshalling response from API response %s", err.Error())
-		return
+		return nil, err
 	}
-	fmt.Printf("Response: %v\n\n", teamResponse)
 	return
 }
 
-// UpdateTeam updates GitHub team
-func (team *Team) UpdateTeam(client http.Client) (err error) {
+// UpdateTeam updates GitHub team. The name is only sent along with a Slug, so a team
+// addressed by its name keeps it even when the name is given as the slug
+func (s *Service) UpdateTeam(ctx context.Context, team *Team) (details *TeamDetails, err error) {
 
-	url := fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, team.Name)
+	url := s.orgURL("/teams/%s", team.path())
+	payload, err := s.resolveParent(ctx, team)
+	if err != nil {
+		return
+	}
+	if !team.sendsName() {
+		unnamed := *payload
+		unnamed.Name = ""
+		payload = &unnamed
+	}
 	// Convert the json body object to bytes
-	jsonValue, err := json.Marshal(team)
+	jsonValue, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("Error in marshalling the request payload")
+		return nil, fmt.Errorf("Error in marshalling the request payload")
 	}
-	response, err := sendHTTPRequest(client, "PATCH", url, bytes.NewBuffer(jsonValue))
-	fmt.Printf("Return status code of the request: %d\n", response.StatusCode)
+	response, err := s.sendHTTPRequest(ctx, "PATCH", url, bytes.NewBuffer(jsonValue))
 	if err != nil {
 		return
 	}
 
 	if response.StatusCode != h.StatusOK {
-		err = fmt.Errorf("Error in updating team : %s", team.Name)
-		return
-	}
-	defer response.Body.Close()
-	// Read the bytes from the response body
-	responseBodyBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("Error in reading response from API response%s", err.Error())
+		err = newAPIError(fmt.Sprintf("Error in updating team : %s", team.Name), response)
 		return
 	}
 
-	var teamResponse interface{}
-	// Convert the bytes in interface
-	err = json.Unmarshal(responseBodyBytes, &teamResponse)
+	details = &TeamDetails{}
+	err = readJSON(response, details)
 	if err != nil {
-		err = fmt.Errorf("Error in unmarshalling response from API response %s", err.Error())
-		return
+		return nil, err
+	}
+	if details.Slug != "" {
+		team.Slug = details.Slug
 	}
-	fmt.Printf("Response: %v\n\n", teamResponse)
 	return
 }
 
 // DeleteTeam deletes GitHub team
-func (team *Team) DeleteTeam(client http.Client) (err error) {
-	url := fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, team.Name)
+func (s *Service) DeleteTeam(ctx context.Context, teamName string) (err error) {
+	url := s.orgURL("/teams/%s", teamPath(teamName))
 
-	response, err := sendHTTPRequest(client, "DELETE", url, nil)
-	fmt.Printf("Return status code of the request: %d\n\n", response.StatusCode)
+	response, err := s.sendHTTPRequest(ctx, "DELETE", url, nil)
 	if err != nil {
 		return
 	}
 
 	if response.StatusCode != h.StatusNoContent {
-		err = fmt.Errorf("Error in deleting team : %s", team.Name)
+		err = newAPIError(fmt.Sprintf("Error in deleting team : %s", teamName), response)
 		return
 	}
 
 	return
 }
 
-// ListMemebersOfTeam gets all memebers part of the Github team
-func (team *Team) ListMemebersOfTeam(client http.Client) (err error) {
-
-	url := fmt.Sprintf("%s/%s/teams/%s/members", baseURL, TestOrg, team.Name)
-
-	response, err := sendHTTPRequest(client, "GET", url, nil)
-	fmt.Printf("Return status code of the request: %d\n\n", response.StatusCode)
+// ListTeams gets all teams of the organization, following every page
+func (s *Service) ListTeams(ctx context.Context, opts *ListOptions) (teams []TeamDetails, err error) {
+	teams = []TeamDetails{}
+	err = s.PaginateTeams(opts).All(ctx, &teams)
 	if err != nil {
-		return
+		return nil, err
 	}
+	return
+}
 
-	if response.StatusCode != h.StatusOK {
-		err = fmt.Errorf("Error in getting members of a team : %s", team.Name)
-		return
-	}
-	defer response.Body.Close()
-	// Read the bytes from the response body
-	responseBodyBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("Error in reading response from API response %s", err.Error())
-		return
-	}
+// PaginateTeams returns a Paginator that streams the teams of the organization page by page
+func (s *Service) PaginateTeams(opts *ListOptions) *Paginator {
+	url := s.orgURL("/teams")
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in listing teams of org : %s", s.Org))
+}
 
-	var teamResponse []interface{}
-	// Convert the bytes in interface
-	err = json.Unmarshal(responseBodyBytes, &teamResponse)
+// ListMemebersOfTeam gets all memebers part of the Github team, following every page
+func (s *Service) ListMemebersOfTeam(ctx context.Context, teamName string, opts *ListOptions) (members []User, err error) {
+	members = []User{}
+	err = s.PaginateMemebersOfTeam(teamName, opts).All(ctx, &members)
 	if err != nil {
-		err = fmt.Errorf("Error in unmarshalling response from API response %s", err.Error())
-		return
+		return nil, err
 	}
-	fmt.Printf("Response: %v\n\n", teamResponse)
 	return
 }
 
+// PaginateMemebersOfTeam returns a Paginator that streams the memebers of the Github team page by page
+func (s *Service) PaginateMemebersOfTeam(teamName string, opts *ListOptions) *Paginator {
+	url := s.orgURL("/teams/%s/members", teamPath(teamName))
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in getting members of a team : %s", teamName))
+}
+
 // AddMemeberToTeam adds memeber to a GitHub team
-func AddMemeberToTeam(client http.Client, teamName, userName, roleType string) (err error) {
+func (s *Service) AddMemeberToTeam(ctx context.Context, teamName, userName, roleType string) (membership *Membership, err error) {
 
-	url := fmt.Sprintf("%s/%s/teams/%s/memberships/%s", baseURL, TestOrg, teamName, userName)
+	url := s.orgURL("/teams/%s/memberships/%s", teamPath(teamName), pathSegment(userName))
 
 	type memeberRole struct {
 		Role string `json:"role,omitempty"`
@@ -224,50 +316,38 @@ func AddMemeberToTeam(client http.Client, teamName, userName, roleType string) (
 	// Convert the json body object to bytes
 	jsonValue, err := json.Marshal(memRole)
 	if err != nil {
-		return fmt.Errorf("Error in marshalling the request payload")
+		return nil, fmt.Errorf("Error in marshalling the request payload")
 	}
 
-	response, err := sendHTTPRequest(client, "PUT", url, bytes.NewBuffer(jsonValue))
-	fmt.Printf("Return status code of the request: %d\n", response.StatusCode)
+	response, err := s.sendHTTPRequest(ctx, "PUT", url, bytes.NewBuffer(jsonValue))
 	if err != nil {
 		return
 	}
 	if response.StatusCode != h.StatusOK {
-		err = fmt.Errorf("Error in adding %s to team %s", userName, teamName)
-		return
-	}
-	defer response.Body.Close()
-	// Read the bytes from the response body
-	responseBodyBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("Error in reading response from API response%s", err.Error())
+		err = newAPIError(fmt.Sprintf("Error in adding %s to team %s", userName, teamName), response)
 		return
 	}
 
-	var teamResponse interface{}
-	// Convert the bytes in interface
-	err = json.Unmarshal(responseBodyBytes, &teamResponse)
+	membership = &Membership{}
+	err = readJSON(response, membership)
 	if err != nil {
-		err = fmt.Errorf("Error in unmarshalling response from API response %s", err.Error())
-		return
+		return nil, err
 	}
-	fmt.Printf("Response: %v\n\n", teamResponse)
 	return
 }
 
 // DeleteMemberFromTeam deletes memeber from GitHub team
-func DeleteMemberFromTeam(client http.Client, teamName, userName string) (err error) {
+func (s *Service) DeleteMemberFromTeam(ctx context.Context, teamName, userName string) (err error) {
 
-	url := fmt.Sprintf("%s/%s/teams/%s/memberships/%s", baseURL, TestOrg, teamName, userName)
+	url := s.orgURL("/teams/%s/memberships/%s", teamPath(teamName), pathSegment(userName))
 
-	response, err := sendHTTPRequest(client, "DELETE", url, nil)
-	fmt.Printf("Return status code of the request: %d\n", response.StatusCode)
+	response, err := s.sendHTTPRequest(ctx, "DELETE", url, nil)
 	if err != nil {
 		return
 	}
 
 	if response.StatusCode != h.StatusNoContent {
-		err = fmt.Errorf("Error in deleting %s from team %s", userName, teamName)
+		err = newAPIError(fmt.Sprintf("Error in deleting %s from team %s", userName, teamName), response)
 		return
 	}
 
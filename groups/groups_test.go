@@ -2,12 +2,15 @@ package groups
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"testing"
 
+	"github.com/HybriStratus/test-github-groups/http/auth"
 	"github.com/HybriStratus/test-github-groups/http/mock"
 )
 
@@ -18,6 +21,42 @@ type responses struct {
 	response http.Response
 }
 
+// TestNewService tests that the service builds URLs for its own org and API root
+func TestNewService(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		org      string
+		apiURL   string
+		expected string
+	}{
+		{
+			name:     "Testing default GitHub API URL",
+			org:      TestOrg,
+			apiURL:   "",
+			expected: "https://api.github.com/orgs/HybriStratus/teams",
+		},
+		{
+			name:     "Testing GitHub Enterprise Server API URL",
+			org:      "other-org",
+			apiURL:   "https://github.example.com/api/v3/",
+			expected: "https://github.example.com/api/v3/orgs/other-org/teams",
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			service := NewService(mock.Client{}, tt.org, tt.apiURL)
+			got := service.orgURL("/teams")
+			if got != tt.expected {
+				t.Errorf("wanted %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
 // TestCreateTeam tests CreateTeam function of a team
 func TestCreateTeam(t *testing.T) {
 
@@ -64,7 +103,7 @@ func TestCreateTeam(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodPost,
-					url:    fmt.Sprintf("%s/%s/teams", baseURL, TestOrg),
+					url:    fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg),
 					response: http.Response{
 						StatusCode: http.StatusCreated,
 						Body:       ConvertBytesToIoReadCloser([]byte(createResponse)),
@@ -75,11 +114,11 @@ func TestCreateTeam(t *testing.T) {
 		{
 			name:     "Testing failure of team creation",
 			payload:  newTeam,
-			expected: fmt.Errorf("Error in creating a new team : test_team"),
+			expected: fmt.Errorf("Error in creating a new team : test_team: 502 Bad Gateway"),
 			requestClients: []responses{
 				{
 					method: http.MethodPost,
-					url:    fmt.Sprintf("%s/%s/teams", baseURL, TestOrg),
+					url:    fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg),
 					response: http.Response{
 						StatusCode: http.StatusBadGateway,
 					},
@@ -97,7 +136,8 @@ func TestCreateTeam(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := tt.payload.CreateTeam(mockClient)
+			service := NewService(mockClient, TestOrg, "")
+			details, got := service.CreateTeam(context.Background(), &tt.payload)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -107,11 +147,14 @@ func TestCreateTeam(t *testing.T) {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
 				}
 			}
+			if got == nil && details.ID != 5714710 {
+				t.Errorf("wanted team id %d, got %d", 5714710, details.ID)
+			}
 		})
 	}
 }
 
-//TestGetTeamDetails tests the GetTeamDetails of a team
+// TestGetTeamDetails tests the GetTeamDetails of a team
 func TestGetTeamDetails(t *testing.T) {
 
 	createResponse := fmt.Sprintf(`{
@@ -154,7 +197,7 @@ func TestGetTeamDetails(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodGet,
-					url:    fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusOK,
 						Body:       ConvertBytesToIoReadCloser([]byte(createResponse)),
@@ -165,11 +208,11 @@ func TestGetTeamDetails(t *testing.T) {
 		{
 			name:     "Testing failure of team details retrival",
 			payload:  newTeam,
-			expected: fmt.Errorf("Error in getting team deatils : test_team"),
+			expected: fmt.Errorf("Error in getting team deatils : test_team: 409 Conflict"),
 			requestClients: []responses{
 				{
 					method: http.MethodGet,
-					url:    fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusConflict,
 					},
@@ -187,7 +230,8 @@ func TestGetTeamDetails(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := tt.payload.GetTeamDetails(mockClient)
+			service := NewService(mockClient, TestOrg, "")
+			details, got := service.GetTeamDetails(context.Background(), tt.payload.Name)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -197,6 +241,9 @@ func TestGetTeamDetails(t *testing.T) {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
 				}
 			}
+			if got == nil && (details.Slug != "test_team" || details.Organization.Login != TestOrg) {
+				t.Errorf("wanted team test_team of %s, got %v", TestOrg, details)
+			}
 		})
 	}
 }
@@ -245,7 +292,7 @@ func TestUpdateTeam(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodPatch,
-					url:    fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusOK,
 						Body:       ConvertBytesToIoReadCloser([]byte(createResponse)),
@@ -256,11 +303,11 @@ func TestUpdateTeam(t *testing.T) {
 		{
 			name:     "Testing failure of update of team",
 			payload:  newTeam,
-			expected: fmt.Errorf("Error in updating team : test_team"),
+			expected: fmt.Errorf("Error in updating team : test_team: 500 Internal Server Error"),
 			requestClients: []responses{
 				{
 					method: http.MethodPatch,
-					url:    fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusInternalServerError,
 					},
@@ -278,7 +325,8 @@ func TestUpdateTeam(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := tt.payload.UpdateTeam(mockClient)
+			service := NewService(mockClient, TestOrg, "")
+			details, got := service.UpdateTeam(context.Background(), &tt.payload)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -288,6 +336,9 @@ func TestUpdateTeam(t *testing.T) {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
 				}
 			}
+			if got == nil && details.Privacy != "secret" {
+				t.Errorf("wanted privacy %s, got %s", "secret", details.Privacy)
+			}
 		})
 	}
 }
@@ -312,7 +363,7 @@ func TestDeleteTeam(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodDelete,
-					url:    fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusNoContent,
 					},
@@ -322,11 +373,11 @@ func TestDeleteTeam(t *testing.T) {
 		{
 			name:     "Testing failure of deletion of team",
 			payload:  newTeam,
-			expected: fmt.Errorf("Error in deleting team : test_team"),
+			expected: fmt.Errorf("Error in deleting team : test_team: 403 Forbidden"),
 			requestClients: []responses{
 				{
 					method: http.MethodDelete,
-					url:    fmt.Sprintf("%s/%s/teams/%s", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusForbidden,
 					},
@@ -344,7 +395,90 @@ func TestDeleteTeam(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := tt.payload.DeleteTeam(mockClient)
+			service := NewService(mockClient, TestOrg, "")
+			got := service.DeleteTeam(context.Background(), tt.payload.Name)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+		})
+	}
+}
+
+// TestListTeams tests ListTeams function of an org
+func TestListTeams(t *testing.T) {
+
+	createResponse := fmt.Sprintf(`
+	[
+    {
+        "name": "test_team",
+        "id": 5714710,
+        "slug": "test_team",
+        "privacy": "closed",
+        "parent": null
+    },
+    {
+        "name": "test_child_team",
+        "id": 5714711,
+        "slug": "test_child_team",
+        "privacy": "closed",
+        "parent": {"name": "test_team", "id": 5714710, "slug": "test_team"}
+    }
+]`)
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		expectedTeams  int
+		expected       error
+	}{
+		{
+			name:          "Testing successful listing of teams",
+			expectedTeams: 2,
+			expected:      nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(createResponse)),
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing failure of listing of teams",
+			expected: fmt.Errorf("Error in listing teams of org : HybriStratus: 401 Unauthorized"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg),
+					response: http.Response{
+						StatusCode: http.StatusUnauthorized,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			teams, got := service.ListTeams(context.Background(), nil)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -354,6 +488,12 @@ func TestDeleteTeam(t *testing.T) {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
 				}
 			}
+			if len(teams) != tt.expectedTeams {
+				t.Errorf("wanted %d teams, got %d", tt.expectedTeams, len(teams))
+			}
+			if got == nil && teams[1].Parent.Slug != "test_team" {
+				t.Errorf("wanted parent test_team, got %v", teams[1].Parent)
+			}
 		})
 	}
 }
@@ -402,7 +542,7 @@ func TestListMemebersOfTeam(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodGet,
-					url:    fmt.Sprintf("%s/%s/teams/%s/members", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/members", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusOK,
 						Body:       ConvertBytesToIoReadCloser([]byte(createResponse)),
@@ -413,11 +553,11 @@ func TestListMemebersOfTeam(t *testing.T) {
 		{
 			name:     "Testing failure of retival of team members",
 			payload:  newTeam,
-			expected: fmt.Errorf("Error in getting members of a team : test_team"),
+			expected: fmt.Errorf("Error in getting members of a team : test_team: 504 Gateway Timeout"),
 			requestClients: []responses{
 				{
 					method: http.MethodGet,
-					url:    fmt.Sprintf("%s/%s/teams/%s/members", baseURL, TestOrg, newTeam.Name),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/members", DefaultAPIURL, TestOrg, newTeam.Name),
 					response: http.Response{
 						StatusCode: http.StatusGatewayTimeout,
 					},
@@ -435,7 +575,8 @@ func TestListMemebersOfTeam(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := tt.payload.ListMemebersOfTeam(mockClient)
+			service := NewService(mockClient, TestOrg, "")
+			members, got := service.ListMemebersOfTeam(context.Background(), tt.payload.Name, nil)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -445,6 +586,9 @@ func TestListMemebersOfTeam(t *testing.T) {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
 				}
 			}
+			if got == nil && (len(members) != 2 || members[1].Login != "test_user2") {
+				t.Errorf("wanted members test_user1 and test_user2, got %v", members)
+			}
 		})
 	}
 }
@@ -481,7 +625,7 @@ func TestAddMemeberToTeam(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodPut,
-					url:    fmt.Sprintf("%s/%s/teams/%s/memberships/%s", baseURL, TestOrg, teamName, userName),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName),
 					response: http.Response{
 						StatusCode: http.StatusOK,
 						Body:       ConvertBytesToIoReadCloser([]byte(createResponse)),
@@ -494,11 +638,11 @@ func TestAddMemeberToTeam(t *testing.T) {
 			team:     teamName,
 			user:     userName,
 			role:     roleType,
-			expected: fmt.Errorf("Error in adding test_user3 to team test_team"),
+			expected: fmt.Errorf("Error in adding test_user3 to team test_team: 422 Unprocessable Entity"),
 			requestClients: []responses{
 				{
 					method: http.MethodPut,
-					url:    fmt.Sprintf("%s/%s/teams/%s/memberships/%s", baseURL, TestOrg, teamName, userName),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName),
 					response: http.Response{
 						StatusCode: http.StatusUnprocessableEntity,
 					},
@@ -516,7 +660,8 @@ func TestAddMemeberToTeam(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := AddMemeberToTeam(mockClient, tt.team, tt.user, tt.role)
+			service := NewService(mockClient, TestOrg, "")
+			membership, got := service.AddMemeberToTeam(context.Background(), tt.team, tt.user, tt.role)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -526,6 +671,9 @@ func TestAddMemeberToTeam(t *testing.T) {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
 				}
 			}
+			if got == nil && (membership.Role != "maintainer" || membership.State != "active") {
+				t.Errorf("wanted active maintainer membership, got %v", membership)
+			}
 		})
 	}
 }
@@ -551,7 +699,7 @@ func TestDeleteMemberFromTeam(t *testing.T) {
 			requestClients: []responses{
 				{
 					method: http.MethodDelete,
-					url:    fmt.Sprintf("%s/%s/teams/%s/memberships/%s", baseURL, TestOrg, teamName, userName),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName),
 					response: http.Response{
 						StatusCode: http.StatusNoContent,
 					},
@@ -562,11 +710,11 @@ func TestDeleteMemberFromTeam(t *testing.T) {
 			name:     "Testing failure of deletion of member from a team",
 			team:     teamName,
 			user:     userName,
-			expected: fmt.Errorf("Error in deleting test_user3 from team test_team"),
+			expected: fmt.Errorf("Error in deleting test_user3 from team test_team: 409 Conflict"),
 			requestClients: []responses{
 				{
 					method: http.MethodDelete,
-					url:    fmt.Sprintf("%s/%s/teams/%s/memberships/%s", baseURL, TestOrg, teamName, userName),
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName),
 					response: http.Response{
 						StatusCode: http.StatusConflict,
 					},
@@ -584,7 +732,8 @@ func TestDeleteMemberFromTeam(t *testing.T) {
 				mockClient.SetResponses(response.method, response.url, response.response)
 			}
 
-			got := DeleteMemberFromTeam(mockClient, tt.team, tt.user)
+			service := NewService(mockClient, TestOrg, "")
+			got := service.DeleteMemberFromTeam(context.Background(), tt.team, tt.user)
 			if got != nil && tt.expected != nil {
 				if got.Error() != tt.expected.Error() {
 					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
@@ -598,6 +747,172 @@ func TestDeleteMemberFromTeam(t *testing.T) {
 	}
 }
 
+// TestContextCancellation tests that cancelled contexts abort the calls
+func TestContextCancellation(t *testing.T) {
+
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "test_team"), http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(`{"name": "test_team"}`)),
+	})
+	service := NewService(mockClient, TestOrg, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := service.GetTeamDetails(ctx, "test_team")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted %v, got %v", context.Canceled, err)
+	}
+
+	_, err = service.ListMemebersOfTeam(ctx, "test_team", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted %v, got %v", context.Canceled, err)
+	}
+}
+
+// TestTokenSource tests that the token of the service TokenSource is sent
+func TestTokenSource(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name          string
+		source        auth.TokenSource
+		expectedAuth  string
+		expectedError bool
+	}{
+		{
+			name:         "Testing static token",
+			source:       auth.StaticToken("ghp_static"),
+			expectedAuth: "Bearer ghp_static",
+		},
+		{
+			name:         "Testing no token source",
+			source:       nil,
+			expectedAuth: "",
+		},
+		{
+			name:          "Testing failing token source",
+			source:        auth.NewFileTokenSource("/nonexistent/token"),
+			expectedError: true,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client and keep the Authorization header it receives
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodDelete, fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "test_team"), http.Response{
+				StatusCode: http.StatusNoContent,
+			})
+			var authorization string
+			service := NewService(clientFunc(func(req *http.Request) (*http.Response, error) {
+				authorization = req.Header.Get("Authorization")
+				return mockClient.Do(req)
+			}), TestOrg, "")
+			service.TokenSource = tt.source
+
+			err := service.DeleteTeam(context.Background(), "test_team")
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("wanted error %v, got %v", tt.expectedError, err)
+			}
+			if authorization != tt.expectedAuth {
+				t.Errorf("wanted %q, got %q", tt.expectedAuth, authorization)
+			}
+		})
+	}
+}
+
+// TestRequestPayloads tests the bodies and headers the team operations send
+func TestRequestPayloads(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg)
+
+	// Create your table test
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		status       int
+		expectedBody string
+		run          func(service *Service) error
+	}{
+		{
+			name:         "Testing create team payload",
+			method:       http.MethodPost,
+			url:          teamURL,
+			status:       http.StatusCreated,
+			expectedBody: `{"name": "test_team", "maintainers": ["alice"], "privacy": "closed", "parent_team_id": 7}`,
+			run: func(service *Service) error {
+				_, err := service.CreateTeam(context.Background(), &Team{Name: "test_team", Maintainers: []string{"alice"}, Privacy: "closed", ParentTeamID: 7})
+				return err
+			},
+		},
+		{
+			name:         "Testing update team payload",
+			method:       http.MethodPatch,
+			url:          teamURL + "/test_team",
+			status:       http.StatusOK,
+			expectedBody: `{"description": "The test team"}`,
+			run: func(service *Service) error {
+				_, err := service.UpdateTeam(context.Background(), &Team{Name: "test_team", Description: "The test team"})
+				return err
+			},
+		},
+		{
+			name:         "Testing default role of added member",
+			method:       http.MethodPut,
+			url:          teamURL + "/test_team/memberships/alice",
+			status:       http.StatusOK,
+			expectedBody: `{"role": "member"}`,
+			run: func(service *Service) error {
+				_, err := service.AddMemeberToTeam(context.Background(), "test_team", "alice", "")
+				return err
+			},
+		},
+		{
+			name:         "Testing role of added maintainer",
+			method:       http.MethodPut,
+			url:          teamURL + "/test_team/memberships/alice",
+			status:       http.StatusOK,
+			expectedBody: `{"role": "maintainer"}`,
+			run: func(service *Service) error {
+				_, err := service.AddMemeberToTeam(context.Background(), "test_team", "alice", RoleMaintainer)
+				return err
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client expecting exactly the payload
+			mockClient := mock.Client{}
+			mockClient.Expect(tt.method, tt.url,
+				mock.JSONBody(tt.expectedBody),
+				mock.Header("Accept", mediaType),
+				mock.Header("Content-Type", mediaType),
+			).Respond(http.Response{StatusCode: tt.status, Body: ConvertBytesToIoReadCloser([]byte(`{}`))})
+
+			service := NewService(mockClient, TestOrg, "")
+			service.TokenSource = nil
+			err := tt.run(service)
+			if err != nil {
+				t.Errorf("wanted no error, got %v", err)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// clientFunc adapts a function to the http.Client interface
+type clientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls the function
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 // Converts an bytes into an io.ReadCloser, which is used as the body of an API call
 func ConvertBytesToIoReadCloser(objectByte []byte) io.ReadCloser {
 	objectReader := bytes.NewReader(objectByte)
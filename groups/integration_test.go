@@ -0,0 +1,328 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/auth"
+	"github.com/HybriStratus/test-github-groups/http/fake"
+	"github.com/HybriStratus/test-github-groups/http/net"
+)
+
+// newFakeService starts a fake GitHub with the test organization and returns a Service using it
+func newFakeService(t *testing.T) (*Service, *fake.Server) {
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+	server.SetToken("fake-token")
+	server.AddOrg(TestOrg)
+	server.AddOrgMember(TestOrg, "alice", "admin")
+	server.AddOrgMember(TestOrg, "bob", "member")
+	server.AddUser("carol")
+	server.AddRepo(TestOrg, "api", true)
+
+	service := NewService(net.Client{}, TestOrg, server.URL)
+	service.TokenSource = auth.StaticToken("fake-token")
+	return service, server
+}
+
+// TestIntegration runs a sequence of operations sharing the state of a fake GitHub
+func TestIntegration(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newFakeService(t)
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		run      func() (interface{}, error)
+		expected string
+		check    func(err error) bool
+	}{
+		{
+			name: "Testing create team",
+			run: func() (interface{}, error) {
+				details, err := service.CreateTeam(ctx, &Team{Name: "Platform Team", Maintainers: []string{"alice"}, Repos: []string{TestOrg + "/api"}})
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%s %s", details.Slug, details.Privacy), nil
+			},
+			expected: "platform-team secret",
+		},
+		{
+			name: "Testing create team with a taken name",
+			run: func() (interface{}, error) {
+				return service.CreateTeam(ctx, &Team{Name: "platform team"})
+			},
+			check: IsAlreadyExists,
+		},
+		{
+			name: "Testing create nested team by parent slug",
+			run: func() (interface{}, error) {
+				details, err := service.CreateTeam(ctx, &Team{Name: "Backend", ParentTeamSlug: "platform-team"})
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%s %s %s", details.Slug, details.Privacy, details.Parent.Slug), nil
+			},
+			expected: "backend closed platform-team",
+		},
+		{
+			name: "Testing get team",
+			run: func() (interface{}, error) {
+				details, err := service.GetTeamDetails(ctx, "Platform Team")
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%d members %d repos", details.MembersCount, details.ReposCount), nil
+			},
+			expected: "1 members 1 repos",
+		},
+		{
+			name: "Testing add members",
+			run: func() (interface{}, error) {
+				report, err := service.AddMembers(ctx, "Backend", []string{"bob", "carol", "nobody"}, RoleMember, nil)
+				if err != nil {
+					return nil, err
+				}
+				var results []string
+				for _, result := range report.Results {
+					results = append(results, fmt.Sprintf("%s %s %s", result.User, result.Status, result.State))
+				}
+				return strings.Join(results, ", "), nil
+			},
+			expected: "bob ok active, carol ok pending, nobody not-found ",
+		},
+		{
+			name: "Testing pending members are not listed",
+			run: func() (interface{}, error) {
+				members, err := service.ListMemebersOfTeam(ctx, "Backend", nil)
+				return len(members), err
+			},
+			expected: "1",
+		},
+		{
+			name: "Testing team invitations",
+			run: func() (interface{}, error) {
+				invitations, err := service.ListTeamInvitations(ctx, "Backend", nil)
+				if err != nil || len(invitations) != 1 {
+					return invitations, err
+				}
+				return invitations[0].Login, nil
+			},
+			expected: "carol",
+		},
+		{
+			name: "Testing team tree",
+			run: func() (interface{}, error) {
+				tree, err := service.GetTeamTree(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%s > %s", tree[0].Slug, tree[0].Children[0].Slug), nil
+			},
+			expected: "platform-team > backend",
+		},
+		{
+			name: "Testing moving a team under its child",
+			run: func() (interface{}, error) {
+				return service.UpdateTeam(ctx, &Team{Name: "Platform Team", ParentTeamSlug: "Backend"})
+			},
+			check: IsValidationFailed,
+		},
+		{
+			name: "Testing invalid repository permission",
+			run: func() (interface{}, error) {
+				return nil, service.SetTeamRepoPermission(ctx, "Platform Team", TestOrg, "api", "write")
+			},
+			check: IsValidationFailed,
+		},
+		{
+			name: "Testing repository permission",
+			run: func() (interface{}, error) {
+				err := service.SetTeamRepoPermission(ctx, "Platform Team", TestOrg, "api", PermissionMaintain)
+				if err != nil {
+					return nil, err
+				}
+				repo, err := service.CheckTeamRepoPermission(ctx, "Platform Team", TestOrg, "api")
+				if err != nil {
+					return nil, err
+				}
+				return repo.Permission(), nil
+			},
+			expected: PermissionMaintain,
+		},
+		{
+			name: "Testing ensure unchanged team",
+			run: func() (interface{}, error) {
+				_, result, err := service.EnsureTeam(ctx, &Team{Name: "Backend", ParentTeamSlug: "platform-team"})
+				if err != nil {
+					return nil, err
+				}
+				return result.Action, nil
+			},
+			expected: TeamUnchanged,
+		},
+		{
+			name: "Testing update by slug keeps the name",
+			run: func() (interface{}, error) {
+				details, err := service.UpdateTeam(ctx, &Team{Name: "platform-team", Description: "The platform"})
+				if err != nil {
+					return nil, err
+				}
+				return details.Name, nil
+			},
+			expected: "Platform Team",
+		},
+		{
+			name: "Testing rename changing the case only",
+			run: func() (interface{}, error) {
+				details, err := service.UpdateTeam(ctx, &Team{Name: "Platform team", Slug: "platform-team"})
+				if err != nil {
+					return nil, err
+				}
+				return details.Name, nil
+			},
+			expected: "Platform team",
+		},
+		{
+			name: "Testing rename team",
+			run: func() (interface{}, error) {
+				details, err := service.UpdateTeam(ctx, &Team{Name: "Platform", Slug: "platform-team"})
+				if err != nil {
+					return nil, err
+				}
+				return details.Slug, nil
+			},
+			expected: "platform",
+		},
+		{
+			name: "Testing delete team with its children",
+			run: func() (interface{}, error) {
+				err := service.DeleteTeam(ctx, "Platform")
+				if err != nil {
+					return nil, err
+				}
+				return service.GetTeamDetails(ctx, "Backend")
+			},
+			check: IsNotFound,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.run()
+			if tt.check != nil {
+				if !tt.check(err) {
+					t.Errorf("wanted a matching error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wanted no error, got %v", err)
+			}
+			if fmt.Sprint(got) != tt.expected {
+				t.Errorf("wanted %q, got %q", tt.expected, fmt.Sprint(got))
+			}
+		})
+	}
+}
+
+// TestIntegrationPagination tests that the listings follow every page of the fake GitHub
+func TestIntegrationPagination(t *testing.T) {
+	ctx := context.Background()
+	service, server := newFakeService(t)
+	for i := 0; i < 7; i++ {
+		_, err := service.CreateTeam(ctx, &Team{Name: fmt.Sprintf("team-%d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Create your table test
+	tests := []struct {
+		name             string
+		opts             *ListOptions
+		expectedTeams    int
+		expectedRequests int
+	}{
+		{
+			name:             "Testing default page size",
+			opts:             nil,
+			expectedTeams:    7,
+			expectedRequests: 1,
+		},
+		{
+			name:             "Testing small pages",
+			opts:             &ListOptions{PerPage: 3},
+			expectedTeams:    7,
+			expectedRequests: 3,
+		},
+		{
+			name:             "Testing starting page",
+			opts:             &ListOptions{PerPage: 3, Page: 2},
+			expectedTeams:    4,
+			expectedRequests: 2,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := server.Requests()
+			teams, err := service.ListTeams(ctx, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(teams) != tt.expectedTeams {
+				t.Errorf("wanted %d teams, got %d", tt.expectedTeams, len(teams))
+			}
+			if requests := server.Requests() - before; requests != tt.expectedRequests {
+				t.Errorf("wanted %d requests, got %d", tt.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+// TestIntegrationLimits tests the errors of rejected credentials and an exceeded rate limit
+func TestIntegrationLimits(t *testing.T) {
+	ctx := context.Background()
+
+	// Create your table test
+	tests := []struct {
+		name  string
+		token string
+		limit int
+		check func(err error) bool
+	}{
+		{
+			name:  "Testing bad credentials",
+			token: "wrong-token",
+			check: IsUnauthorized,
+		},
+		{
+			name:  "Testing exceeded rate limit",
+			token: "fake-token",
+			limit: 1,
+			check: IsRateLimited,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, server := newFakeService(t)
+			service.TokenSource = auth.StaticToken(tt.token)
+			if tt.limit > 0 {
+				server.SetRateLimit(tt.limit)
+				_, err := service.ListTeams(ctx, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := service.ListTeams(ctx, nil)
+			if !tt.check(err) {
+				t.Errorf("wanted a matching error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package groups
+
+// Logger records the requests of a Service. Its methods take a message followed by
+// alternating keys and values, like the ones of log/slog, so a *slog.Logger can be used as is
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// nopLogger discards everything, it keeps the library silent by default
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+func (nopLogger) Info(msg string, args ...interface{})  {}
+func (nopLogger) Warn(msg string, args ...interface{})  {}
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
+// logger returns the Logger of the service, discarding everything when it is nil
+func (s *Service) logger() Logger {
+	if s.Logger == nil {
+		return nopLogger{}
+	}
+	return s.Logger
+}
@@ -0,0 +1,140 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// entry is a message recorded by recordingLogger
+type entry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger is a Logger keeping every message
+type recordingLogger struct {
+	entries []entry
+}
+
+func (l *recordingLogger) record(level, msg string, args []interface{}) {
+	fields := map[string]interface{}{}
+	for i := 0; i+1 < len(args); i += 2 {
+		fields[fmt.Sprint(args[i])] = args[i+1]
+	}
+	l.entries = append(l.entries, entry{level: level, msg: msg, fields: fields})
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) { l.record("debug", msg, args) }
+func (l *recordingLogger) Info(msg string, args ...interface{})  { l.record("info", msg, args) }
+func (l *recordingLogger) Warn(msg string, args ...interface{})  { l.record("warn", msg, args) }
+func (l *recordingLogger) Error(msg string, args ...interface{}) { l.record("error", msg, args) }
+
+// TestLogger tests the requests logged by the service
+func TestLogger(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "test_team")
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		expectedMsg    string
+		expectedFields map[string]interface{}
+	}{
+		{
+			name: "Testing logged request",
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    teamURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"X-Github-Request-Id": {"CAFE:1"}},
+						Body:       ConvertBytesToIoReadCloser([]byte(`{"name": "test_team"}`)),
+					},
+				},
+			},
+			expectedMsg: "github request",
+			expectedFields: map[string]interface{}{
+				"method":     http.MethodGet,
+				"url":        teamURL,
+				"status":     http.StatusOK,
+				"request_id": "CAFE:1",
+			},
+		},
+		{
+			name:        "Testing logged failure",
+			expectedMsg: "github request failed",
+			expectedFields: map[string]interface{}{
+				"method": http.MethodGet,
+				"url":    teamURL,
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			logger := &recordingLogger{}
+			service := NewService(mockClient, TestOrg, "")
+			service.Logger = logger
+			service.GetTeamDetails(context.Background(), "test_team")
+
+			if len(logger.entries) != 1 {
+				t.Fatalf("wanted 1 entry, got %v", logger.entries)
+			}
+			got := logger.entries[0]
+			if got.level != "debug" || got.msg != tt.expectedMsg {
+				t.Errorf("wanted debug %q, got %s %q", tt.expectedMsg, got.level, got.msg)
+			}
+			for key, value := range tt.expectedFields {
+				if got.fields[key] != value {
+					t.Errorf("wanted %s %v, got %v", key, value, got.fields[key])
+				}
+			}
+			if _, ok := got.fields["duration"].(time.Duration); !ok {
+				t.Errorf("wanted a duration, got %v", got.fields["duration"])
+			}
+		})
+	}
+}
+
+// TestSilentByDefault tests that the service writes nothing to stdout without a Logger
+func TestSilentByDefault(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "test_team")
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodDelete, teamURL, http.Response{StatusCode: http.StatusNoContent})
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	err = NewService(mockClient, TestOrg, "").DeleteTeam(context.Background(), "test_team")
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written, _ := ioutil.ReadAll(r)
+	if len(written) != 0 {
+		t.Errorf("wanted nothing written to stdout, got %q", written)
+	}
+}
@@ -0,0 +1,96 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	h "net/http"
+	"net/url"
+)
+
+// Roles of the members of a team
+const (
+	RoleMember     = "member"
+	RoleMaintainer = "maintainer"
+	// RoleAll lists the members of every role
+	RoleAll = "all"
+)
+
+// States of a team membership, pending until the user accepts the invitation to the organization
+const (
+	StateActive  = "active"
+	StatePending = "pending"
+)
+
+// ListMemebersOfTeamByRole gets the active memebers of the Github team with role, following every page
+func (s *Service) ListMemebersOfTeamByRole(ctx context.Context, teamName, role string, opts *ListOptions) (members []User, err error) {
+	members = []User{}
+	err = s.PaginateMemebersOfTeamByRole(teamName, role, opts).All(ctx, &members)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateMemebersOfTeamByRole returns a Paginator that streams the memebers of the Github team
+// with role page by page
+func (s *Service) PaginateMemebersOfTeamByRole(teamName, role string, opts *ListOptions) *Paginator {
+	rawURL := s.orgURL("/teams/%s/members", teamPath(teamName))
+	if role != "" {
+		rawURL += "?role=" + url.QueryEscape(role)
+	}
+	return s.newPaginator(rawURL, opts, fmt.Sprintf("Error in getting %s members of a team : %s", role, teamName))
+}
+
+// GetMembership gets the role of the user in the GitHub team and whether the membership is
+// active or pending. A user outside the team gets an error matched by IsNotFound
+func (s *Service) GetMembership(ctx context.Context, teamName, userName string) (membership *Membership, err error) {
+
+	url := s.orgURL("/teams/%s/memberships/%s", teamPath(teamName), pathSegment(userName))
+
+	response, err := s.sendHTTPRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusOK {
+		err = newAPIError(fmt.Sprintf("Error in getting membership of %s in team %s", userName, teamName), response)
+		return
+	}
+
+	membership = &Membership{}
+	err = readJSON(response, membership)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// SetMemberRole promotes or demotes a memeber of the GitHub team to role. Unlike
+// AddMemeberToTeam it never adds users outside the team, and it makes no change
+// when the user already has the role
+func (s *Service) SetMemberRole(ctx context.Context, teamName, userName, role string) (membership *Membership, err error) {
+	membership, err = s.GetMembership(ctx, teamName, userName)
+	if err != nil {
+		return nil, err
+	}
+	if membership.Role == role {
+		return
+	}
+	return s.AddMemeberToTeam(ctx, teamName, userName, role)
+}
+
+// ListTeamInvitations gets the pending invitations of users invited to the organization
+// through the GitHub team, following every page
+func (s *Service) ListTeamInvitations(ctx context.Context, teamName string, opts *ListOptions) (invitations []Invitation, err error) {
+	invitations = []Invitation{}
+	err = s.PaginateTeamInvitations(teamName, opts).All(ctx, &invitations)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateTeamInvitations returns a Paginator that streams the pending invitations of the GitHub team page by page
+func (s *Service) PaginateTeamInvitations(teamName string, opts *ListOptions) *Paginator {
+	url := s.orgURL("/teams/%s/invitations", teamPath(teamName))
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in getting invitations of a team : %s", teamName))
+}
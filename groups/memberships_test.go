@@ -0,0 +1,286 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestListMemebersOfTeamByRole tests the role filter of the member listing
+func TestListMemebersOfTeamByRole(t *testing.T) {
+
+	teamName := "test_team"
+	// Create your table test
+	tests := []struct {
+		name           string
+		role           string
+		opts           *ListOptions
+		requestClients []responses
+		expectedUsers  int
+		expected       error
+	}{
+		{
+			name:          "Testing listing of maintainers",
+			role:          RoleMaintainer,
+			expectedUsers: 1,
+			expected:      nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/members?role=maintainer", DefaultAPIURL, TestOrg, teamName),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "alice"}]`)),
+					},
+				},
+			},
+		},
+		{
+			name:          "Testing listing of members with pagination",
+			role:          RoleMember,
+			opts:          &ListOptions{PerPage: 100},
+			expectedUsers: 2,
+			expected:      nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/members?per_page=100&role=member", DefaultAPIURL, TestOrg, teamName),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "bob"}, {"login": "carol"}]`)),
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing failure of listing by role",
+			role:     RoleMaintainer,
+			expected: fmt.Errorf("Error in getting maintainer members of a team : test_team: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/members?role=maintainer", DefaultAPIURL, TestOrg, teamName),
+					response: http.Response{
+						StatusCode: http.StatusNotFound,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			members, got := service.ListMemebersOfTeamByRole(context.Background(), teamName, tt.role, tt.opts)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if len(members) != tt.expectedUsers {
+				t.Errorf("wanted %d members, got %d", tt.expectedUsers, len(members))
+			}
+		})
+	}
+}
+
+// TestGetMembership tests GetMembership function of a team
+func TestGetMembership(t *testing.T) {
+
+	teamName := "test_team"
+	userName := "test_user"
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		expectedRole   string
+		expectedState  string
+		expected       error
+	}{
+		{
+			name:          "Testing pending membership",
+			expectedRole:  RoleMaintainer,
+			expectedState: StatePending,
+			expected:      nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "maintainer", "state": "pending"}`)),
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing user outside the team",
+			expected: fmt.Errorf("Error in getting membership of test_user in team test_team: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName),
+					response: http.Response{
+						StatusCode: http.StatusNotFound,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			membership, got := service.GetMembership(context.Background(), teamName, userName)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if got == nil && (membership.Role != tt.expectedRole || membership.State != tt.expectedState) {
+				t.Errorf("wanted %s %s, got %s %s", tt.expectedRole, tt.expectedState, membership.Role, membership.State)
+			}
+		})
+	}
+}
+
+// TestSetMemberRole tests SetMemberRole function of a team
+func TestSetMemberRole(t *testing.T) {
+
+	teamName := "test_team"
+	userName := "test_user"
+	membershipURL := fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", DefaultAPIURL, TestOrg, teamName, userName)
+	// Create your table test
+	tests := []struct {
+		name           string
+		role           string
+		requestClients []responses
+		expectedRole   string
+		expected       error
+	}{
+		{
+			name:         "Testing promotion to maintainer",
+			role:         RoleMaintainer,
+			expectedRole: RoleMaintainer,
+			expected:     nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    membershipURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "active"}`)),
+					},
+				},
+				{
+					method: http.MethodPut,
+					url:    membershipURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "maintainer", "state": "active"}`)),
+					},
+				},
+			},
+		},
+		{
+			name:         "Testing role already held",
+			role:         RoleMember,
+			expectedRole: RoleMember,
+			expected:     nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    membershipURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "active"}`)),
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing user outside the team",
+			role:     RoleMaintainer,
+			expected: fmt.Errorf("Error in getting membership of test_user in team test_team: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    membershipURL,
+					response: http.Response{
+						StatusCode: http.StatusNotFound,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			membership, got := service.SetMemberRole(context.Background(), teamName, userName, tt.role)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if got == nil && membership.Role != tt.expectedRole {
+				t.Errorf("wanted role %s, got %s", tt.expectedRole, membership.Role)
+			}
+		})
+	}
+}
+
+// TestListTeamInvitations tests ListTeamInvitations function of a team
+func TestListTeamInvitations(t *testing.T) {
+
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, fmt.Sprintf("%s/orgs/%s/teams/%s/invitations", DefaultAPIURL, TestOrg, "test_team"), http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(`[{"id": 1, "login": "newcomer", "role": "direct_member", "inviter": {"login": "alice"}}]`)),
+	})
+
+	service := NewService(mockClient, TestOrg, "")
+	invitations, err := service.ListTeamInvitations(context.Background(), "test_team", nil)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	if len(invitations) != 1 || invitations[0].Login != "newcomer" || invitations[0].Inviter.Login != "alice" {
+		t.Errorf("wanted the invitation of newcomer by alice, got %v", invitations)
+	}
+}
@@ -0,0 +1,117 @@
+package groups
+
+// Organization is the GitHub organization a team belongs to
+type Organization struct {
+	Login       string `json:"login"`
+	ID          int    `json:"id"`
+	NodeID      string `json:"node_id"`
+	URL         string `json:"url"`
+	ReposURL    string `json:"repos_url"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+// TeamDetails is a team as returned by the GitHub API
+type TeamDetails struct {
+	ID           int           `json:"id"`
+	NodeID       string        `json:"node_id"`
+	Name         string        `json:"name"`
+	Slug         string        `json:"slug"`
+	Description  string        `json:"description"`
+	Privacy      string        `json:"privacy"`
+	Permission   string        `json:"permission"`
+	URL          string        `json:"url"`
+	HTMLURL      string        `json:"html_url"`
+	MembersCount int           `json:"members_count"`
+	ReposCount   int           `json:"repos_count"`
+	Parent       *TeamDetails  `json:"parent"`
+	Organization *Organization `json:"organization"`
+}
+
+// User is a GitHub user as returned by the member listings
+type User struct {
+	Login     string `json:"login"`
+	ID        int    `json:"id"`
+	NodeID    string `json:"node_id"`
+	URL       string `json:"url"`
+	HTMLURL   string `json:"html_url"`
+	Type      string `json:"type"`
+	SiteAdmin bool   `json:"site_admin"`
+}
+
+// Membership is the role and state of a user in a team
+type Membership struct {
+	URL   string `json:"url"`
+	Role  string `json:"role"`
+	State string `json:"state"`
+}
+
+// Repository is a repository a team has access to, as returned by the team repository endpoints
+type Repository struct {
+	ID          int                    `json:"id"`
+	NodeID      string                 `json:"node_id"`
+	Name        string                 `json:"name"`
+	FullName    string                 `json:"full_name"`
+	Private     bool                   `json:"private"`
+	URL         string                 `json:"url"`
+	HTMLURL     string                 `json:"html_url"`
+	Owner       *User                  `json:"owner"`
+	Permissions *RepositoryPermissions `json:"permissions"`
+	// RoleName is the role of the team on the repository, it names custom roles as well
+	RoleName string `json:"role_name"`
+}
+
+// RepositoryPermissions are the permissions a team has on a repository
+type RepositoryPermissions struct {
+	Admin    bool `json:"admin"`
+	Maintain bool `json:"maintain"`
+	Push     bool `json:"push"`
+	Triage   bool `json:"triage"`
+	Pull     bool `json:"pull"`
+}
+
+// Permission returns the role of the team on the repository, RoleName when GitHub sent it
+// and otherwise the highest of the permissions
+func (r *Repository) Permission() string {
+	if r.RoleName != "" {
+		return r.RoleName
+	}
+	if r.Permissions == nil {
+		return ""
+	}
+	switch {
+	case r.Permissions.Admin:
+		return PermissionAdmin
+	case r.Permissions.Maintain:
+		return PermissionMaintain
+	case r.Permissions.Push:
+		return PermissionPush
+	case r.Permissions.Triage:
+		return PermissionTriage
+	case r.Permissions.Pull:
+		return PermissionPull
+	}
+	return ""
+}
+
+// Invitation is a pending invitation of a user to the organization
+type Invitation struct {
+	ID                 int    `json:"id"`
+	Login              string `json:"login"`
+	Email              string `json:"email"`
+	Role               string `json:"role"`
+	CreatedAt          string `json:"created_at"`
+	Inviter            *User  `json:"inviter"`
+	TeamCount          int    `json:"team_count"`
+	InvitationTeamsURL string `json:"invitation_teams_url"`
+}
+
+// OrgMembership is the role and state of a user in the organization
+type OrgMembership struct {
+	URL             string        `json:"url"`
+	State           string        `json:"state"`
+	Role            string        `json:"role"`
+	OrganizationURL string        `json:"organization_url"`
+	Organization    *Organization `json:"organization"`
+	User            *User         `json:"user"`
+}
@@ -0,0 +1,265 @@
+package groups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	h "net/http"
+	"net/url"
+)
+
+// Roles of the members of an organization
+const (
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// Roles of the users invited to an organization
+const (
+	InvitationRoleAdmin          = "admin"
+	InvitationRoleDirectMember   = "direct_member"
+	InvitationRoleBillingManager = "billing_manager"
+)
+
+// InvitationRequest invites a user, given by exactly one of Login, InviteeID or Email,
+// to the organization and the teams listed
+type InvitationRequest struct {
+	// Login is the username of the invitee, its ID is looked up
+	Login     string `json:"-"`
+	InviteeID int    `json:"invitee_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	// Role is one of the InvitationRole constants, direct_member when empty
+	Role    string `json:"role,omitempty"`
+	TeamIDs []int  `json:"team_ids,omitempty"`
+	// TeamSlugs name teams by slug or name, their IDs are looked up and added to TeamIDs
+	TeamSlugs []string `json:"-"`
+}
+
+// GetUser gets a GitHub user by login
+func (s *Service) GetUser(ctx context.Context, userName string) (user *User, err error) {
+
+	url := fmt.Sprintf("%s/users/%s", s.APIURL, pathSegment(userName))
+
+	response, err := s.sendHTTPRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusOK {
+		err = newAPIError(fmt.Sprintf("Error in getting user : %s", userName), response)
+		return
+	}
+
+	user = &User{}
+	err = readJSON(response, user)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetOrgMembership gets the role of the user in the organization and whether the membership
+// is active or pending. A user outside the organization gets an error matched by IsNotFound
+func (s *Service) GetOrgMembership(ctx context.Context, userName string) (membership *OrgMembership, err error) {
+
+	url := s.orgURL("/memberships/%s", pathSegment(userName))
+
+	response, err := s.sendHTTPRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusOK {
+		err = newAPIError(fmt.Sprintf("Error in getting membership of %s in org %s", userName, s.Org), response)
+		return
+	}
+
+	membership = &OrgMembership{}
+	err = readJSON(response, membership)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// SetOrgMembership sets the role of the user in the organization, users outside the
+// organization are invited and stay pending until they accept
+func (s *Service) SetOrgMembership(ctx context.Context, userName, role string) (membership *OrgMembership, err error) {
+
+	url := s.orgURL("/memberships/%s", pathSegment(userName))
+
+	type orgRole struct {
+		Role string `json:"role,omitempty"`
+	}
+	// Convert the json body object to bytes
+	jsonValue, err := json.Marshal(orgRole{Role: role})
+	if err != nil {
+		return nil, fmt.Errorf("Error in marshalling the request payload")
+	}
+
+	response, err := s.sendHTTPRequest(ctx, "PUT", url, bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusOK {
+		err = newAPIError(fmt.Sprintf("Error in setting membership of %s in org %s", userName, s.Org), response)
+		return
+	}
+
+	membership = &OrgMembership{}
+	err = readJSON(response, membership)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// RemoveOrgMembership removes the user from the organization and all its teams, or
+// cancels the pending invitation of the user
+func (s *Service) RemoveOrgMembership(ctx context.Context, userName string) (err error) {
+
+	url := s.orgURL("/memberships/%s", pathSegment(userName))
+
+	response, err := s.sendHTTPRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusNoContent {
+		err = newAPIError(fmt.Sprintf("Error in removing %s from org %s", userName, s.Org), response)
+		return
+	}
+	return
+}
+
+// ListOrgMembers gets the members of the organization with role, admin or member, or every
+// member when role is empty, following every page
+func (s *Service) ListOrgMembers(ctx context.Context, role string, opts *ListOptions) (members []User, err error) {
+	members = []User{}
+	err = s.PaginateOrgMembers(role, opts).All(ctx, &members)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateOrgMembers returns a Paginator that streams the members of the organization with role page by page
+func (s *Service) PaginateOrgMembers(role string, opts *ListOptions) *Paginator {
+	rawURL := s.orgURL("/members")
+	if role != "" {
+		rawURL += "?role=" + url.QueryEscape(role)
+	}
+	return s.newPaginator(rawURL, opts, fmt.Sprintf("Error in listing members of org : %s", s.Org))
+}
+
+// ListOutsideCollaborators gets the users with access to repositories of the organization
+// without being members of it, following every page
+func (s *Service) ListOutsideCollaborators(ctx context.Context, opts *ListOptions) (users []User, err error) {
+	users = []User{}
+	err = s.PaginateOutsideCollaborators(opts).All(ctx, &users)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateOutsideCollaborators returns a Paginator that streams the outside collaborators of the organization page by page
+func (s *Service) PaginateOutsideCollaborators(opts *ListOptions) *Paginator {
+	url := s.orgURL("/outside_collaborators")
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in listing outside collaborators of org : %s", s.Org))
+}
+
+// CreateInvitation invites a user to the organization, looking up the IDs of the login and
+// of the team slugs of invitation first
+func (s *Service) CreateInvitation(ctx context.Context, invitation *InvitationRequest) (created *Invitation, err error) {
+	invitees := 0
+	for _, set := range []bool{invitation.Login != "", invitation.InviteeID != 0, invitation.Email != ""} {
+		if set {
+			invitees++
+		}
+	}
+	if invitees != 1 {
+		return nil, fmt.Errorf("Error in creating invitation : exactly one of login, invitee ID or email is required")
+	}
+
+	payload := *invitation
+	if payload.Login != "" {
+		user, err := s.GetUser(ctx, payload.Login)
+		if err != nil {
+			return nil, err
+		}
+		payload.InviteeID = user.ID
+	}
+	payload.TeamIDs = append([]int{}, invitation.TeamIDs...)
+	for _, teamName := range payload.TeamSlugs {
+		team, err := s.GetTeamDetails(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		payload.TeamIDs = append(payload.TeamIDs, team.ID)
+	}
+
+	url := s.orgURL("/invitations")
+	// Convert the json body object to bytes
+	jsonValue, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("Error in marshalling the request payload")
+	}
+
+	response, err := s.sendHTTPRequest(ctx, "POST", url, bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusCreated {
+		err = newAPIError(fmt.Sprintf("Error in inviting %s to org %s", invitation.invitee(), s.Org), response)
+		return
+	}
+
+	created = &Invitation{}
+	err = readJSON(response, created)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// invitee describes the invited user in errors
+func (invitation *InvitationRequest) invitee() string {
+	switch {
+	case invitation.Login != "":
+		return invitation.Login
+	case invitation.Email != "":
+		return invitation.Email
+	}
+	return fmt.Sprintf("user %d", invitation.InviteeID)
+}
+
+// ListInvitations gets the pending invitations of the organization, following every page
+func (s *Service) ListInvitations(ctx context.Context, opts *ListOptions) (invitations []Invitation, err error) {
+	invitations = []Invitation{}
+	err = s.PaginateInvitations(opts).All(ctx, &invitations)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateInvitations returns a Paginator that streams the pending invitations of the organization page by page
+func (s *Service) PaginateInvitations(opts *ListOptions) *Paginator {
+	url := s.orgURL("/invitations")
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in listing invitations of org : %s", s.Org))
+}
+
+// CancelInvitation cancels a pending invitation of the organization
+func (s *Service) CancelInvitation(ctx context.Context, invitationID int) (err error) {
+
+	url := s.orgURL("/invitations/%d", invitationID)
+
+	response, err := s.sendHTTPRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusNoContent {
+		err = newAPIError(fmt.Sprintf("Error in cancelling invitation %d of org %s", invitationID, s.Org), response)
+		return
+	}
+	return
+}
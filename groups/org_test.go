@@ -0,0 +1,355 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestOrgMembership tests GetOrgMembership, SetOrgMembership and RemoveOrgMembership
+func TestOrgMembership(t *testing.T) {
+
+	membershipURL := fmt.Sprintf("%s/orgs/%s/memberships/%s", DefaultAPIURL, TestOrg, "test_user")
+	membershipResponse := `{"state": "pending", "role": "admin", "user": {"login": "test_user"}}`
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		call           func(service *Service) (*OrgMembership, error)
+		expectedState  string
+		expected       error
+	}{
+		{
+			name: "Testing successful get of org membership",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      membershipURL,
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(membershipResponse))},
+				},
+			},
+			call: func(service *Service) (*OrgMembership, error) {
+				return service.GetOrgMembership(context.Background(), "test_user")
+			},
+			expectedState: StatePending,
+		},
+		{
+			name: "Testing failure of get of org membership",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      membershipURL,
+					response: http.Response{StatusCode: http.StatusNotFound},
+				},
+			},
+			call: func(service *Service) (*OrgMembership, error) {
+				return service.GetOrgMembership(context.Background(), "test_user")
+			},
+			expected: fmt.Errorf("Error in getting membership of test_user in org HybriStratus: 404 Not Found"),
+		},
+		{
+			name: "Testing successful set of org role",
+			requestClients: []responses{
+				{
+					method:   http.MethodPut,
+					url:      membershipURL,
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(membershipResponse))},
+				},
+			},
+			call: func(service *Service) (*OrgMembership, error) {
+				return service.SetOrgMembership(context.Background(), "test_user", OrgRoleAdmin)
+			},
+			expectedState: StatePending,
+		},
+		{
+			name: "Testing failure of set of org role",
+			requestClients: []responses{
+				{
+					method:   http.MethodPut,
+					url:      membershipURL,
+					response: http.Response{StatusCode: http.StatusUnprocessableEntity},
+				},
+			},
+			call: func(service *Service) (*OrgMembership, error) {
+				return service.SetOrgMembership(context.Background(), "test_user", "owner")
+			},
+			expected: fmt.Errorf("Error in setting membership of test_user in org HybriStratus: 422 Unprocessable Entity"),
+		},
+		{
+			name: "Testing successful removal from org",
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      membershipURL,
+					response: http.Response{StatusCode: http.StatusNoContent},
+				},
+			},
+			call: func(service *Service) (*OrgMembership, error) {
+				return nil, service.RemoveOrgMembership(context.Background(), "test_user")
+			},
+		},
+		{
+			name: "Testing failure of removal from org",
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      membershipURL,
+					response: http.Response{StatusCode: http.StatusForbidden},
+				},
+			},
+			call: func(service *Service) (*OrgMembership, error) {
+				return nil, service.RemoveOrgMembership(context.Background(), "test_user")
+			},
+			expected: fmt.Errorf("Error in removing test_user from org HybriStratus: 403 Forbidden"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			membership, got := tt.call(service)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if got == nil && membership != nil && membership.State != tt.expectedState {
+				t.Errorf("wanted state %s, got %s", tt.expectedState, membership.State)
+			}
+		})
+	}
+}
+
+// TestListOrgUsers tests ListOrgMembers and ListOutsideCollaborators
+func TestListOrgUsers(t *testing.T) {
+
+	orgURL := fmt.Sprintf("%s/orgs/%s", DefaultAPIURL, TestOrg)
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		call           func(service *Service) ([]User, error)
+		expectedUsers  int
+		expected       error
+	}{
+		{
+			name: "Testing listing of org admins",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      orgURL + "/members?role=admin",
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`[{"login": "alice"}]`))},
+				},
+			},
+			call: func(service *Service) ([]User, error) {
+				return service.ListOrgMembers(context.Background(), OrgRoleAdmin, nil)
+			},
+			expectedUsers: 1,
+		},
+		{
+			name: "Testing listing of every org member",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      orgURL + "/members",
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`[{"login": "alice"}, {"login": "bob"}]`))},
+				},
+			},
+			call: func(service *Service) ([]User, error) {
+				return service.ListOrgMembers(context.Background(), "", nil)
+			},
+			expectedUsers: 2,
+		},
+		{
+			name: "Testing listing of outside collaborators",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      orgURL + "/outside_collaborators",
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`[{"login": "contractor"}]`))},
+				},
+			},
+			call: func(service *Service) ([]User, error) {
+				return service.ListOutsideCollaborators(context.Background(), nil)
+			},
+			expectedUsers: 1,
+		},
+		{
+			name: "Testing failure of listing of outside collaborators",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      orgURL + "/outside_collaborators",
+					response: http.Response{StatusCode: http.StatusForbidden},
+				},
+			},
+			call: func(service *Service) ([]User, error) {
+				return service.ListOutsideCollaborators(context.Background(), nil)
+			},
+			expected: fmt.Errorf("Error in listing outside collaborators of org : HybriStratus: 403 Forbidden"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			users, got := tt.call(service)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if len(users) != tt.expectedUsers {
+				t.Errorf("wanted %d users, got %d", tt.expectedUsers, len(users))
+			}
+		})
+	}
+}
+
+// TestCreateInvitation tests CreateInvitation function of an org
+func TestCreateInvitation(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		invitation     InvitationRequest
+		requestClients []responses
+		expectedBody   string
+		expected       error
+	}{
+		{
+			name:       "Testing invitation by login with teams",
+			invitation: InvitationRequest{Login: "newcomer", TeamIDs: []int{7}, TeamSlugs: []string{"Platform Team"}},
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      fmt.Sprintf("%s/users/%s", DefaultAPIURL, "newcomer"),
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"login": "newcomer", "id": 42}`))},
+				},
+				{
+					method:   http.MethodGet,
+					url:      fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "platform-team"),
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"id": 9}`))},
+				},
+			},
+			expectedBody: `{"invitee_id":42,"team_ids":[7,9]}`,
+		},
+		{
+			name:         "Testing invitation by email",
+			invitation:   InvitationRequest{Email: "newcomer@example.com", Role: InvitationRoleAdmin},
+			expectedBody: `{"email":"newcomer@example.com","role":"admin"}`,
+		},
+		{
+			name:       "Testing invitation of unknown login",
+			invitation: InvitationRequest{Login: "ghost"},
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      fmt.Sprintf("%s/users/%s", DefaultAPIURL, "ghost"),
+					response: http.Response{StatusCode: http.StatusNotFound},
+				},
+			},
+			expected: fmt.Errorf("Error in getting user : ghost: 404 Not Found"),
+		},
+		{
+			name:       "Testing invitation with two invitees",
+			invitation: InvitationRequest{Login: "newcomer", Email: "newcomer@example.com"},
+			expected:   fmt.Errorf("Error in creating invitation : exactly one of login, invitee ID or email is required"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client and answer the invitation with the body it receives
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+			var body string
+			service := NewService(clientFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodPost || req.URL.String() != fmt.Sprintf("%s/orgs/%s/invitations", DefaultAPIURL, TestOrg) {
+					return mockClient.Do(req)
+				}
+				data, _ := ioutil.ReadAll(req.Body)
+				body = string(data)
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 1, "role": "direct_member"}`)),
+				}, nil
+			}), TestOrg, "")
+
+			invitation, got := service.CreateInvitation(context.Background(), &tt.invitation)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if body != tt.expectedBody {
+				t.Errorf("wanted body %s, got %s", tt.expectedBody, body)
+			}
+			if got == nil && invitation.ID != 1 {
+				t.Errorf("wanted invitation 1, got %d", invitation.ID)
+			}
+		})
+	}
+}
+
+// TestInvitations tests ListInvitations and CancelInvitation functions of an org
+func TestInvitations(t *testing.T) {
+
+	invitationsURL := fmt.Sprintf("%s/orgs/%s/invitations", DefaultAPIURL, TestOrg)
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, invitationsURL, http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(`[{"id": 1, "email": "newcomer@example.com", "team_count": 2}]`)),
+	})
+	mockClient.SetResponses(http.MethodDelete, invitationsURL+"/1", http.Response{StatusCode: http.StatusNoContent})
+	mockClient.SetResponses(http.MethodDelete, invitationsURL+"/2", http.Response{StatusCode: http.StatusNotFound})
+
+	service := NewService(mockClient, TestOrg, "")
+	invitations, err := service.ListInvitations(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	if len(invitations) != 1 || invitations[0].TeamCount != 2 {
+		t.Errorf("wanted one invitation to two teams, got %v", invitations)
+	}
+	if err := service.CancelInvitation(context.Background(), 1); err != nil {
+		t.Errorf("wanted no error, got %v", err)
+	}
+	expected := "Error in cancelling invitation 2 of org HybriStratus: 404 Not Found"
+	if err := service.CancelInvitation(context.Background(), 2); err == nil || err.Error() != expected {
+		t.Errorf("wanted %s, got %v", expected, err)
+	}
+}
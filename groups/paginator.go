@@ -0,0 +1,115 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	h "net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// ListOptions specifies the pagination of the list calls
+type ListOptions struct {
+	// PerPage is the number of items requested per page, GitHub allows at most 100
+	PerPage int
+	// Page is the page to start listing from
+	Page int
+}
+
+// linkNextRegexp matches the rel="next" entry of a Link header
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="next"`)
+
+// Paginator walks through the pages of a GitHub list endpoint by following
+// the rel="next" entries of the Link response header
+type Paginator struct {
+	service *Service
+	nextURL string
+	op      string
+	err     error
+}
+
+// newPaginator creates a Paginator starting at rawURL. op describes the listing
+// in the errors of pages that can not be fetched
+func (s *Service) newPaginator(rawURL string, opts *ListOptions, op string) *Paginator {
+	p := &Paginator{
+		service: s,
+		nextURL: rawURL,
+		op:      op,
+	}
+	if opts == nil || (opts.PerPage == 0 && opts.Page == 0) {
+		return p
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		p.err = fmt.Errorf("Error in parsing list url %s", err.Error())
+		return p
+	}
+	query := u.Query()
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	u.RawQuery = query.Encode()
+	p.nextURL = u.String()
+	return p
+}
+
+// Next fetches the next page and unmarshals its items into page, which must be a
+// pointer to a slice. It returns false once all pages were read or an error occurred
+func (p *Paginator) Next(ctx context.Context, page interface{}) bool {
+	if p.err != nil || p.nextURL == "" {
+		return false
+	}
+
+	response, err := p.service.sendHTTPRequest(ctx, "GET", p.nextURL, nil)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	if response.StatusCode != h.StatusOK {
+		p.err = newAPIError(p.op, response)
+		return false
+	}
+
+	p.nextURL = ""
+	if match := linkNextRegexp.FindStringSubmatch(response.Header.Get("Link")); match != nil {
+		p.nextURL = match[1]
+	}
+
+	err = readJSON(response, page)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	return true
+}
+
+// Err returns the error that stopped the pagination, if any
+func (p *Paginator) Err() error {
+	return p.err
+}
+
+// All fetches all remaining pages and appends their items to items, which must be
+// a pointer to a slice
+func (p *Paginator) All(ctx context.Context, items interface{}) error {
+	itemsValue := reflect.ValueOf(items)
+	if itemsValue.Kind() != reflect.Ptr || itemsValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Error in collecting pages, expected a pointer to a slice got %T", items)
+	}
+
+	all := itemsValue.Elem()
+	for {
+		page := reflect.New(all.Type())
+		if !p.Next(ctx, page.Interface()) {
+			break
+		}
+		all = reflect.AppendSlice(all, page.Elem())
+	}
+	itemsValue.Elem().Set(all)
+	return p.err
+}
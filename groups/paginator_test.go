@@ -0,0 +1,163 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestPaginator tests that list calls follow the rel="next" links of the Link header
+func TestPaginator(t *testing.T) {
+
+	firstPage := `[{"login": "test_user1", "id": 1}, {"login": "test_user2", "id": 2}]`
+	secondPage := `[{"login": "test_user3", "id": 3}]`
+	membersURL := fmt.Sprintf("%s/orgs/%s/teams/%s/members", DefaultAPIURL, TestOrg, "test_team")
+	firstURL := membersURL + "?per_page=2"
+	secondURL := membersURL + "?page=2&per_page=2"
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		expectedUsers  []string
+		expectedPages  int
+		expected       error
+	}{
+		{
+			name: "Testing collection of all pages",
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    firstURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Header: http.Header{
+							"Link": {fmt.Sprintf(`<%s>; rel="next", <%s>; rel="last"`, secondURL, secondURL)},
+						},
+						Body: ConvertBytesToIoReadCloser([]byte(firstPage)),
+					},
+				},
+				{
+					method: http.MethodGet,
+					url:    secondURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Header: http.Header{
+							"Link": {fmt.Sprintf(`<%s>; rel="prev", <%s>; rel="first"`, firstURL, firstURL)},
+						},
+						Body: ConvertBytesToIoReadCloser([]byte(secondPage)),
+					},
+				},
+			},
+			expectedUsers: []string{"test_user1", "test_user2", "test_user3"},
+			expectedPages: 2,
+			expected:      nil,
+		},
+		{
+			name: "Testing failure on a later page",
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    firstURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Header: http.Header{
+							"Link": {fmt.Sprintf(`<%s>; rel="next"`, secondURL)},
+						},
+						Body: ConvertBytesToIoReadCloser([]byte(firstPage)),
+					},
+				},
+				{
+					method: http.MethodGet,
+					url:    secondURL,
+					response: http.Response{
+						StatusCode: http.StatusBadGateway,
+					},
+				},
+			},
+			expectedUsers: []string{"test_user1", "test_user2"},
+			expectedPages: 1,
+			expected:      fmt.Errorf("Error in getting members of a team : test_team: 502 Bad Gateway"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+			service := NewService(mockClient, TestOrg, "")
+
+			// Stream the pages one by one
+			pages := 0
+			var users []string
+			paginator := service.PaginateMemebersOfTeam("test_team", &ListOptions{PerPage: 2})
+			for {
+				var page []User
+				if !paginator.Next(context.Background(), &page) {
+					break
+				}
+				pages++
+				for _, user := range page {
+					users = append(users, user.Login)
+				}
+			}
+
+			got := paginator.Err()
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if pages != tt.expectedPages {
+				t.Errorf("wanted %d pages, got %d", tt.expectedPages, pages)
+			}
+			if fmt.Sprint(users) != fmt.Sprint(tt.expectedUsers) {
+				t.Errorf("wanted %v, got %v", tt.expectedUsers, users)
+			}
+		})
+	}
+}
+
+// TestPaginatorAll tests that All collects the items of every page
+func TestPaginatorAll(t *testing.T) {
+
+	membersURL := fmt.Sprintf("%s/orgs/%s/teams/%s/members", DefaultAPIURL, TestOrg, "test_team")
+	secondURL := membersURL + "?page=2"
+
+	// Create the mock Client
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, membersURL, http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Link": {fmt.Sprintf(`<%s>; rel="next"`, secondURL)}},
+		Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "test_user1"}]`)),
+	})
+	mockClient.SetResponses(http.MethodGet, secondURL, http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "test_user2"}]`)),
+	})
+	service := NewService(mockClient, TestOrg, "")
+
+	members, err := service.ListMemebersOfTeam(context.Background(), "test_team", nil)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	if len(members) != 2 || members[0].Login != "test_user1" || members[1].Login != "test_user2" {
+		t.Errorf("wanted test_user1 and test_user2, got %v", members)
+	}
+
+	var notSlice User
+	if err := service.PaginateMemebersOfTeam("test_team", nil).All(context.Background(), &notSlice); err == nil {
+		t.Errorf("wanted an error when collecting into %T", notSlice)
+	}
+}
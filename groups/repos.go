@@ -0,0 +1,102 @@
+package groups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	h "net/http"
+)
+
+// Permissions a team can be granted on a repository, organizations may define custom roles as well
+const (
+	PermissionPull     = "pull"
+	PermissionTriage   = "triage"
+	PermissionPush     = "push"
+	PermissionMaintain = "maintain"
+	PermissionAdmin    = "admin"
+)
+
+// repositoryMediaType makes the check permission endpoint return the repository instead of an empty body
+const repositoryMediaType = "application/vnd.github.v3.repository+json"
+
+// ListTeamRepos gets all repositories the GitHub team has access to, following every page
+func (s *Service) ListTeamRepos(ctx context.Context, teamName string, opts *ListOptions) (repos []Repository, err error) {
+	repos = []Repository{}
+	err = s.PaginateTeamRepos(teamName, opts).All(ctx, &repos)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateTeamRepos returns a Paginator that streams the repositories of the GitHub team page by page
+func (s *Service) PaginateTeamRepos(teamName string, opts *ListOptions) *Paginator {
+	url := s.orgURL("/teams/%s/repos", teamPath(teamName))
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in getting repositories of a team : %s", teamName))
+}
+
+// SetTeamRepoPermission grants the GitHub team permission on owner/repo, or changes the
+// permission it already has. An empty permission keeps the default of GitHub, pull
+func (s *Service) SetTeamRepoPermission(ctx context.Context, teamName, owner, repo, permission string) (err error) {
+
+	url := s.orgURL("/teams/%s/repos/%s/%s", teamPath(teamName), pathSegment(owner), pathSegment(repo))
+
+	type repoPermission struct {
+		Permission string `json:"permission,omitempty"`
+	}
+	// Convert the json body object to bytes
+	jsonValue, err := json.Marshal(repoPermission{Permission: permission})
+	if err != nil {
+		return fmt.Errorf("Error in marshalling the request payload")
+	}
+
+	response, err := s.sendHTTPRequest(ctx, "PUT", url, bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusNoContent {
+		err = newAPIError(fmt.Sprintf("Error in granting team %s access to %s/%s", teamName, owner, repo), response)
+		return
+	}
+	return
+}
+
+// RemoveTeamRepo removes the access of the GitHub team to owner/repo
+func (s *Service) RemoveTeamRepo(ctx context.Context, teamName, owner, repo string) (err error) {
+
+	url := s.orgURL("/teams/%s/repos/%s/%s", teamPath(teamName), pathSegment(owner), pathSegment(repo))
+
+	response, err := s.sendHTTPRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusNoContent {
+		err = newAPIError(fmt.Sprintf("Error in removing team %s access to %s/%s", teamName, owner, repo), response)
+		return
+	}
+	return
+}
+
+// CheckTeamRepoPermission gets owner/repo with the permissions the GitHub team has on it.
+// A team without access gets an error matched by IsNotFound
+func (s *Service) CheckTeamRepoPermission(ctx context.Context, teamName, owner, repo string) (repository *Repository, err error) {
+
+	url := s.orgURL("/teams/%s/repos/%s/%s", teamPath(teamName), pathSegment(owner), pathSegment(repo))
+
+	response, err := s.sendHTTPRequestWithAccept(ctx, "GET", url, repositoryMediaType, nil)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusOK {
+		err = newAPIError(fmt.Sprintf("Error in checking team %s access to %s/%s", teamName, owner, repo), response)
+		return
+	}
+
+	repository = &Repository{}
+	err = readJSON(response, repository)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
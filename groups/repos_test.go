@@ -0,0 +1,307 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestListTeamRepos tests ListTeamRepos function of a team
+func TestListTeamRepos(t *testing.T) {
+
+	listResponse := fmt.Sprintf(`
+	[
+    {
+        "id": 1296269,
+        "name": "Hello-World",
+        "full_name": "HybriStratus/Hello-World",
+        "private": false,
+        "owner": {"login": "HybriStratus", "id": 97473700, "type": "Organization"},
+        "permissions": {"admin": false, "maintain": true, "push": true, "triage": true, "pull": true},
+        "role_name": "maintain"
+    },
+    {
+        "id": 1296270,
+        "name": "docs",
+        "full_name": "HybriStratus/docs",
+        "private": true,
+        "permissions": {"admin": false, "maintain": false, "push": false, "triage": false, "pull": true}
+    }
+]`)
+
+	teamName := "test_team"
+	// Create your table test
+	tests := []struct {
+		name                string
+		requestClients      []responses
+		team                string
+		expectedPermissions []string
+		expected            error
+	}{
+		{
+			name:                "Testing successful listing of team repositories",
+			team:                teamName,
+			expectedPermissions: []string{PermissionMaintain, PermissionPull},
+			expected:            nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/repos", DefaultAPIURL, TestOrg, teamName),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(listResponse)),
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing failure of listing of team repositories",
+			team:     teamName,
+			expected: fmt.Errorf("Error in getting repositories of a team : test_team: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/repos", DefaultAPIURL, TestOrg, teamName),
+					response: http.Response{
+						StatusCode: http.StatusNotFound,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			repos, got := service.ListTeamRepos(context.Background(), tt.team, nil)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if len(repos) != len(tt.expectedPermissions) {
+				t.Fatalf("wanted %d repositories, got %d", len(tt.expectedPermissions), len(repos))
+			}
+			for i, repo := range repos {
+				if repo.Permission() != tt.expectedPermissions[i] {
+					t.Errorf("wanted permission %s on %s, got %s", tt.expectedPermissions[i], repo.FullName, repo.Permission())
+				}
+			}
+		})
+	}
+}
+
+// TestSetTeamRepoPermission tests SetTeamRepoPermission function of a team
+func TestSetTeamRepoPermission(t *testing.T) {
+
+	teamName := "test_team"
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		permission     string
+		expected       error
+	}{
+		{
+			name:       "Testing successful grant of repository permission",
+			permission: PermissionPush,
+			expected:   nil,
+			requestClients: []responses{
+				{
+					method: http.MethodPut,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", DefaultAPIURL, TestOrg, teamName, TestOrg, "Hello-World"),
+					response: http.Response{
+						StatusCode: http.StatusNoContent,
+					},
+				},
+			},
+		},
+		{
+			name:       "Testing failure of grant of unknown repository role",
+			permission: "custom-role",
+			expected:   fmt.Errorf("Error in granting team test_team access to HybriStratus/Hello-World: 422 Unprocessable Entity"),
+			requestClients: []responses{
+				{
+					method: http.MethodPut,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", DefaultAPIURL, TestOrg, teamName, TestOrg, "Hello-World"),
+					response: http.Response{
+						StatusCode: http.StatusUnprocessableEntity,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			got := service.SetTeamRepoPermission(context.Background(), teamName, TestOrg, "Hello-World", tt.permission)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+		})
+	}
+}
+
+// TestRemoveTeamRepo tests RemoveTeamRepo function of a team
+func TestRemoveTeamRepo(t *testing.T) {
+
+	teamName := "test_team"
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		expected       error
+	}{
+		{
+			name:     "Testing successful removal of repository access",
+			expected: nil,
+			requestClients: []responses{
+				{
+					method: http.MethodDelete,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", DefaultAPIURL, TestOrg, teamName, TestOrg, "Hello-World"),
+					response: http.Response{
+						StatusCode: http.StatusNoContent,
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing failure of removal of repository access",
+			expected: fmt.Errorf("Error in removing team test_team access to HybriStratus/Hello-World: 403 Forbidden"),
+			requestClients: []responses{
+				{
+					method: http.MethodDelete,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", DefaultAPIURL, TestOrg, teamName, TestOrg, "Hello-World"),
+					response: http.Response{
+						StatusCode: http.StatusForbidden,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			got := service.RemoveTeamRepo(context.Background(), teamName, TestOrg, "Hello-World")
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+		})
+	}
+}
+
+// TestCheckTeamRepoPermission tests CheckTeamRepoPermission function of a team
+func TestCheckTeamRepoPermission(t *testing.T) {
+
+	checkResponse := fmt.Sprintf(`{
+    "id": 1296269,
+    "name": "Hello-World",
+    "full_name": "HybriStratus/Hello-World",
+    "permissions": {"admin": false, "maintain": false, "push": true, "triage": true, "pull": true}
+}`)
+
+	teamName := "test_team"
+	// Create your table test
+	tests := []struct {
+		name               string
+		response           http.Response
+		expectedPermission string
+		expectedNotFound   bool
+		expected           error
+	}{
+		{
+			name:               "Testing team with access to the repository",
+			expectedPermission: PermissionPush,
+			expected:           nil,
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(checkResponse)),
+			},
+		},
+		{
+			name:             "Testing team without access to the repository",
+			expectedNotFound: true,
+			expected:         fmt.Errorf("Error in checking team test_team access to HybriStratus/Hello-World: 404 Not Found"),
+			response: http.Response{
+				StatusCode: http.StatusNotFound,
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			url := fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", DefaultAPIURL, TestOrg, teamName, TestOrg, "Hello-World")
+			client := clientFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodGet || req.URL.String() != url {
+					t.Fatalf("unexpected request %s %s", req.Method, req.URL)
+				}
+				if accept := req.Header.Get("Accept"); accept != repositoryMediaType {
+					t.Errorf("wanted Accept %s, got %s", repositoryMediaType, accept)
+				}
+				response := tt.response
+				return &response, nil
+			})
+
+			service := NewService(client, TestOrg, "")
+			repo, got := service.CheckTeamRepoPermission(context.Background(), teamName, TestOrg, "Hello-World")
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if IsNotFound(got) != tt.expectedNotFound {
+				t.Errorf("wanted IsNotFound %v, got %v", tt.expectedNotFound, IsNotFound(got))
+			}
+			if got == nil && repo.Permission() != tt.expectedPermission {
+				t.Errorf("wanted permission %s, got %s", tt.expectedPermission, repo.Permission())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package groups
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// TeamFilter selects teams of the organization, empty fields match every team
+type TeamFilter struct {
+	// NamePrefix matches the teams whose name or slug starts with it, ignoring case
+	NamePrefix string
+	// NameRegexp matches the teams whose name it matches
+	NameRegexp *regexp.Regexp
+	// Privacy matches the teams with this privacy, secret or closed
+	Privacy string
+	// Parent matches the child teams of the team with this name or slug
+	Parent string
+	// RootOnly matches the teams without a parent
+	RootOnly bool
+	// MinMembers and MaxMembers bound the number of members, counting them costs a
+	// request per team left by the other fields
+	MinMembers *int
+	MaxMembers *int
+}
+
+// Int returns a pointer to v, for the member bounds of TeamFilter
+func Int(v int) *int {
+	return &v
+}
+
+// SearchTeams lists every team of the organization and keeps the ones matching filter.
+// With member bounds the teams are returned with MembersCount filled in
+func (s *Service) SearchTeams(ctx context.Context, filter *TeamFilter, opts *ListOptions) (teams []TeamDetails, err error) {
+	all, err := s.ListTeams(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return all, nil
+	}
+
+	teams = []TeamDetails{}
+	for _, team := range all {
+		if !filter.matches(&team) {
+			continue
+		}
+		if filter.MinMembers != nil || filter.MaxMembers != nil {
+			// The listing does not include members_count, only the team itself does
+			details, err := s.GetTeamDetails(ctx, team.Slug)
+			if err != nil {
+				return nil, err
+			}
+			team.MembersCount = details.MembersCount
+			if filter.MinMembers != nil && team.MembersCount < *filter.MinMembers {
+				continue
+			}
+			if filter.MaxMembers != nil && team.MembersCount > *filter.MaxMembers {
+				continue
+			}
+		}
+		teams = append(teams, team)
+	}
+	return
+}
+
+// matches checks team against every field of the filter except the member bounds
+func (filter *TeamFilter) matches(team *TeamDetails) bool {
+	if filter.NamePrefix != "" {
+		prefix := strings.ToLower(filter.NamePrefix)
+		if !strings.HasPrefix(strings.ToLower(team.Name), prefix) && !strings.HasPrefix(team.Slug, prefix) {
+			return false
+		}
+	}
+	if filter.NameRegexp != nil && !filter.NameRegexp.MatchString(team.Name) {
+		return false
+	}
+	if filter.Privacy != "" && filter.Privacy != team.Privacy {
+		return false
+	}
+	if filter.RootOnly && team.Parent != nil {
+		return false
+	}
+	if filter.Parent != "" {
+		if team.Parent == nil || (team.Parent.Slug != Slugify(filter.Parent) && !strings.EqualFold(team.Parent.Name, filter.Parent)) {
+			return false
+		}
+	}
+	return true
+}
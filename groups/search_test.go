@@ -0,0 +1,148 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestSearchTeams tests the filters of SearchTeams
+func TestSearchTeams(t *testing.T) {
+
+	listResponse := `
+[
+    {"id": 1, "name": "Platform", "slug": "platform", "privacy": "closed", "parent": null},
+    {"id": 2, "name": "platform-backend", "slug": "platform-backend", "privacy": "closed", "parent": {"id": 1, "name": "Platform", "slug": "platform"}},
+    {"id": 3, "name": "platform-secrets", "slug": "platform-secrets", "privacy": "secret", "parent": {"id": 1, "name": "Platform", "slug": "platform"}},
+    {"id": 4, "name": "Frontend", "slug": "frontend", "privacy": "closed", "parent": null}
+]`
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg)
+	// The bodies are read once, so every test gets its own responses
+	detailsResponses := func() []responses {
+		return []responses{
+			{
+				method:   http.MethodGet,
+				url:      teamURL + "/platform",
+				response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"id": 1, "members_count": 5}`))},
+			},
+			{
+				method:   http.MethodGet,
+				url:      teamURL + "/platform-backend",
+				response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"id": 2, "members_count": 0}`))},
+			},
+			{
+				method:   http.MethodGet,
+				url:      teamURL + "/platform-secrets",
+				response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"id": 3, "members_count": 2}`))},
+			},
+			{
+				method:   http.MethodGet,
+				url:      teamURL + "/frontend",
+				response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"id": 4, "members_count": 12}`))},
+			},
+		}
+	}
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		filter         *TeamFilter
+		requestClients []responses
+		expectedSlugs  string
+		expected       error
+	}{
+		{
+			name:          "Testing no filter",
+			filter:        nil,
+			expectedSlugs: "platform platform-backend platform-secrets frontend",
+		},
+		{
+			name:          "Testing name prefix ignoring case",
+			filter:        &TeamFilter{NamePrefix: "PLATFORM"},
+			expectedSlugs: "platform platform-backend platform-secrets",
+		},
+		{
+			name:          "Testing name regexp",
+			filter:        &TeamFilter{NameRegexp: regexp.MustCompile(`end$`)},
+			expectedSlugs: "platform-backend frontend",
+		},
+		{
+			name:          "Testing privacy",
+			filter:        &TeamFilter{Privacy: "secret"},
+			expectedSlugs: "platform-secrets",
+		},
+		{
+			name:          "Testing parent by name",
+			filter:        &TeamFilter{Parent: "platform", Privacy: "closed"},
+			expectedSlugs: "platform-backend",
+		},
+		{
+			name:          "Testing root teams",
+			filter:        &TeamFilter{RootOnly: true},
+			expectedSlugs: "platform frontend",
+		},
+		{
+			name:           "Testing empty teams",
+			filter:         &TeamFilter{MaxMembers: Int(0)},
+			requestClients: detailsResponses(),
+			expectedSlugs:  "platform-backend",
+		},
+		{
+			name:           "Testing member count range",
+			filter:         &TeamFilter{MinMembers: Int(2), MaxMembers: Int(10)},
+			requestClients: detailsResponses(),
+			expectedSlugs:  "platform platform-secrets",
+		},
+		{
+			name:     "Testing failure of counting members",
+			filter:   &TeamFilter{MinMembers: Int(1), RootOnly: true},
+			expected: fmt.Errorf("Error in getting team deatils : platform: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL + "/platform",
+					response: http.Response{StatusCode: http.StatusNotFound},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodGet, teamURL, http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(listResponse)),
+			})
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			teams, got := service.SearchTeams(context.Background(), tt.filter, nil)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			var slugs []string
+			for _, team := range teams {
+				slugs = append(slugs, team.Slug)
+			}
+			if strings.Join(slugs, " ") != tt.expectedSlugs {
+				t.Errorf("wanted %s, got %s", tt.expectedSlugs, strings.Join(slugs, " "))
+			}
+		})
+	}
+}
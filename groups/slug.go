@@ -0,0 +1,37 @@
+package groups
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Slugify derives the slug GitHub gives a team name: lowercase, every run of characters
+// other than letters, digits, "_" and "-" replaced by a single "-", without leading or
+// trailing "-". Slugs are left unchanged, so names and slugs can be given alike
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		// "-" and the replaced characters collapse into a single "-"
+		if !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// teamPath returns the escaped URL path segment of the team with the name or slug
+func teamPath(name string) string {
+	return url.PathEscape(Slugify(name))
+}
+
+// pathSegment escapes a user or repository name for a URL path
+func pathSegment(name string) string {
+	return url.PathEscape(name)
+}
@@ -0,0 +1,145 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// TestSlugify tests the slugs derived from team names
+func TestSlugify(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		team     string
+		expected string
+	}{
+		{name: "Testing slug", team: "test_team", expected: "test_team"},
+		{name: "Testing capitals and spaces", team: "Platform Team", expected: "platform-team"},
+		{name: "Testing punctuation runs", team: "Ops & Infra (EU)", expected: "ops-infra-eu"},
+		{name: "Testing leading and trailing punctuation", team: "--Core!--", expected: "core"},
+		{name: "Testing dots and slashes", team: "web.frontend/ui", expected: "web-frontend-ui"},
+		{name: "Testing non ASCII letters", team: "Équipe Café", expected: "quipe-caf"},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.team)
+			if got != tt.expected {
+				t.Errorf("wanted %v, got %v", tt.expected, got)
+			}
+			if Slugify(got) != got {
+				t.Errorf("wanted slug %v unchanged, got %v", got, Slugify(got))
+			}
+		})
+	}
+}
+
+// TestTeamSlugURLs tests that teams are addressed by slug whether a name or slug is given
+func TestTeamSlugURLs(t *testing.T) {
+
+	teamResponse := `{"id": 1, "name": "Platform Team", "slug": "platform-team"}`
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "platform-team")
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		call           func(service *Service) error
+	}{
+		{
+			name: "Testing lookup by name",
+			requestClients: []responses{
+				{
+					method:   http.MethodGet,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(teamResponse))},
+				},
+			},
+			call: func(service *Service) error {
+				_, err := service.GetTeamDetails(context.Background(), "Platform Team")
+				return err
+			},
+		},
+		{
+			name: "Testing lookup by slug",
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusNoContent},
+				},
+			},
+			call: func(service *Service) error {
+				return service.DeleteTeam(context.Background(), "platform-team")
+			},
+		},
+		{
+			name: "Testing escaping of user names",
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      teamURL + "/memberships/user%2Fname",
+					response: http.Response{StatusCode: http.StatusNoContent},
+				},
+			},
+			call: func(service *Service) error {
+				return service.DeleteMemberFromTeam(context.Background(), "Platform Team", "user/name")
+			},
+		},
+		{
+			name: "Testing rename through the slug of the created team",
+			requestClients: []responses{
+				{
+					method:   http.MethodPost,
+					url:      fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg),
+					response: http.Response{StatusCode: http.StatusCreated, Body: ConvertBytesToIoReadCloser([]byte(teamResponse))},
+				},
+				{
+					method:   http.MethodPatch,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusOK, Body: ConvertBytesToIoReadCloser([]byte(`{"id": 1, "name": "Infra", "slug": "infra"}`))},
+				},
+			},
+			call: func(service *Service) error {
+				team := &Team{Name: "Platform Team"}
+				_, err := service.CreateTeam(context.Background(), team)
+				if err != nil {
+					return err
+				}
+				if team.Slug != "platform-team" {
+					return fmt.Errorf("wanted slug platform-team, got %s", team.Slug)
+				}
+				team.Name = "Infra"
+				_, err = service.UpdateTeam(context.Background(), team)
+				if err != nil {
+					return err
+				}
+				if team.Slug != "infra" {
+					return fmt.Errorf("wanted slug infra, got %s", team.Slug)
+				}
+				return nil
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			got := tt.call(service)
+			if got != nil {
+				t.Errorf("wanted %v, got %v", nil, got.Error())
+			}
+		})
+	}
+}
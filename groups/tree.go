@@ -0,0 +1,148 @@
+package groups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	h "net/http"
+	"strings"
+)
+
+// TeamNode is a team of the organization team tree with its child teams
+type TeamNode struct {
+	TeamDetails
+	Children []*TeamNode `json:"children,omitempty"`
+}
+
+// resolveParent returns team with ParentTeamID looked up from ParentTeamSlug, team itself
+// is left unchanged
+func (s *Service) resolveParent(ctx context.Context, team *Team) (*Team, error) {
+	if team.ParentTeamSlug == "" || team.ParentTeamID != 0 {
+		return team, nil
+	}
+	parent, err := s.GetTeamDetails(ctx, team.ParentTeamSlug)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *team
+	resolved.ParentTeamID = parent.ID
+	return &resolved, nil
+}
+
+// ListChildTeams gets the teams directly nested under the GitHub team, following every page
+func (s *Service) ListChildTeams(ctx context.Context, teamName string, opts *ListOptions) (teams []TeamDetails, err error) {
+	teams = []TeamDetails{}
+	err = s.PaginateChildTeams(teamName, opts).All(ctx, &teams)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// PaginateChildTeams returns a Paginator that streams the child teams of the GitHub team page by page
+func (s *Service) PaginateChildTeams(teamName string, opts *ListOptions) *Paginator {
+	url := s.orgURL("/teams/%s/teams", teamPath(teamName))
+	return s.newPaginator(url, opts, fmt.Sprintf("Error in getting child teams of a team : %s", teamName))
+}
+
+// GetTeamTree gets every team of the organization arranged by parent, it returns the root teams
+func (s *Service) GetTeamTree(ctx context.Context) ([]*TeamNode, error) {
+	teams, err := s.ListTeams(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return buildTeamTree(teams), nil
+}
+
+// buildTeamTree nests teams under their parents keeping the listing order. Teams whose
+// parent is not listed are returned as roots
+func buildTeamTree(teams []TeamDetails) []*TeamNode {
+	nodes := make(map[int]*TeamNode, len(teams))
+	for _, team := range teams {
+		nodes[team.ID] = &TeamNode{TeamDetails: team}
+	}
+
+	roots := []*TeamNode{}
+	for _, team := range teams {
+		node := nodes[team.ID]
+		if team.Parent != nil {
+			if parent, ok := nodes[team.Parent.ID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// MoveTeam nests the GitHub team, with its whole subtree, under parentName or makes it a
+// root team when parentName is empty. Both teams can be given by name or slug. A move
+// under the team itself or one of its descendants is refused before the team is updated
+func (s *Service) MoveTeam(ctx context.Context, teamName, parentName string) (details *TeamDetails, err error) {
+	teams, err := s.ListTeams(ctx, nil)
+	if err != nil {
+		return
+	}
+	team := findTeam(teams, teamName)
+	if team == nil {
+		return nil, fmt.Errorf("Error in moving team %s : team not found", teamName)
+	}
+
+	// A nil parent_team_id makes the team a root team
+	var parentID interface{}
+	if parentName != "" {
+		parent := findTeam(teams, parentName)
+		if parent == nil {
+			return nil, fmt.Errorf("Error in moving team %s : parent team %s not found", teamName, parentName)
+		}
+		parents := map[int]int{}
+		for _, t := range teams {
+			if t.Parent != nil {
+				parents[t.ID] = t.Parent.ID
+			}
+		}
+		// Reaching the team while walking up from the new parent means the move creates a cycle
+		for id := parent.ID; id != 0; id = parents[id] {
+			if id == team.ID {
+				return nil, fmt.Errorf("Error in moving team %s : %s is the team or one of its descendants", teamName, parentName)
+			}
+		}
+		parentID = parent.ID
+	}
+
+	url := s.orgURL("/teams/%s", pathSegment(team.Slug))
+	// Convert the json body object to bytes, only the parent is sent so the name is kept
+	jsonValue, err := json.Marshal(map[string]interface{}{
+		"parent_team_id": parentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error in marshalling the request payload")
+	}
+	response, err := s.sendHTTPRequest(ctx, "PATCH", url, bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return
+	}
+	if response.StatusCode != h.StatusOK {
+		err = newAPIError(fmt.Sprintf("Error in moving team : %s", teamName), response)
+		return
+	}
+
+	details = &TeamDetails{}
+	err = readJSON(response, details)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// findTeam returns the team of teams with the slug or name, nil when there is none
+func findTeam(teams []TeamDetails, name string) *TeamDetails {
+	for i := range teams {
+		if teams[i].Slug == Slugify(name) || strings.EqualFold(teams[i].Name, name) {
+			return &teams[i]
+		}
+	}
+	return nil
+}
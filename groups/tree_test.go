@@ -0,0 +1,292 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// treeResponse lists an organization with the teams platform > backend > database and frontend
+const treeResponse = `
+[
+    {"id": 1, "name": "Platform", "slug": "platform", "parent": null},
+    {"id": 2, "name": "Backend", "slug": "backend", "parent": {"id": 1, "slug": "platform"}},
+    {"id": 3, "name": "Database", "slug": "database", "parent": {"id": 2, "slug": "backend"}},
+    {"id": 4, "name": "Frontend", "slug": "frontend", "parent": null}
+]`
+
+// TestCreateTeamParentSlug tests that CreateTeam looks up the ID of the parent slug
+func TestCreateTeamParentSlug(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		team           Team
+		requestClients []responses
+		expectedBody   string
+		expected       error
+	}{
+		{
+			name:         "Testing parent given by slug",
+			team:         Team{Name: "database", ParentTeamSlug: "backend"},
+			expectedBody: `{"name":"database","parent_team_id":2}`,
+			expected:     nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "backend"),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 2, "name": "Backend", "slug": "backend"}`)),
+					},
+				},
+			},
+		},
+		{
+			name:         "Testing parent ID taking precedence over the slug",
+			team:         Team{Name: "database", ParentTeamID: 7, ParentTeamSlug: "backend"},
+			expectedBody: `{"name":"database","parent_team_id":7}`,
+			expected:     nil,
+		},
+		{
+			name:     "Testing unknown parent slug",
+			team:     Team{Name: "database", ParentTeamSlug: "missing"},
+			expected: fmt.Errorf("Error in getting team deatils : missing: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s", DefaultAPIURL, TestOrg, "missing"),
+					response: http.Response{
+						StatusCode: http.StatusNotFound,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client and answer the creation with the body it receives
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+			var body string
+			service := NewService(clientFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodPost {
+					return mockClient.Do(req)
+				}
+				data, _ := ioutil.ReadAll(req.Body)
+				body = string(data)
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 3, "name": "database"}`)),
+				}, nil
+			}), TestOrg, "")
+
+			_, got := service.CreateTeam(context.Background(), &tt.team)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if body != tt.expectedBody {
+				t.Errorf("wanted body %s, got %s", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+// TestListChildTeams tests ListChildTeams function of a team
+func TestListChildTeams(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		requestClients []responses
+		expectedTeams  int
+		expected       error
+	}{
+		{
+			name:          "Testing successful listing of child teams",
+			expectedTeams: 1,
+			expected:      nil,
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/teams", DefaultAPIURL, TestOrg, "backend"),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ConvertBytesToIoReadCloser([]byte(`[{"id": 3, "name": "Database", "slug": "database"}]`)),
+					},
+				},
+			},
+		},
+		{
+			name:     "Testing failure of listing of child teams",
+			expected: fmt.Errorf("Error in getting child teams of a team : backend: 404 Not Found"),
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams/%s/teams", DefaultAPIURL, TestOrg, "backend"),
+					response: http.Response{
+						StatusCode: http.StatusNotFound,
+					},
+				},
+			},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+
+			service := NewService(mockClient, TestOrg, "")
+			teams, got := service.ListChildTeams(context.Background(), "backend", nil)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if len(teams) != tt.expectedTeams {
+				t.Errorf("wanted %d teams, got %d", tt.expectedTeams, len(teams))
+			}
+		})
+	}
+}
+
+// TestGetTeamTree tests that GetTeamTree nests the teams under their parents
+func TestGetTeamTree(t *testing.T) {
+
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg), http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(treeResponse)),
+	})
+
+	service := NewService(mockClient, TestOrg, "")
+	roots, err := service.GetTeamTree(context.Background())
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+
+	var render func(nodes []*TeamNode) string
+	render = func(nodes []*TeamNode) string {
+		var parts []string
+		for _, node := range nodes {
+			part := node.Slug
+			if len(node.Children) > 0 {
+				part += "(" + render(node.Children) + ")"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, " ")
+	}
+	expected := "platform(backend(database)) frontend"
+	if got := render(roots); got != expected {
+		t.Errorf("wanted %s, got %s", expected, got)
+	}
+}
+
+// TestMoveTeam tests MoveTeam function of a team
+func TestMoveTeam(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name         string
+		team         string
+		parent       string
+		expectedBody string
+		expected     error
+	}{
+		{
+			name:         "Testing move under another team",
+			team:         "Frontend",
+			parent:       "database",
+			expectedBody: `{"parent_team_id":3}`,
+			expected:     nil,
+		},
+		{
+			name:         "Testing move to the root",
+			team:         "backend",
+			parent:       "",
+			expectedBody: `{"parent_team_id":null}`,
+			expected:     nil,
+		},
+		{
+			name:     "Testing move under a descendant",
+			team:     "platform",
+			parent:   "database",
+			expected: fmt.Errorf("Error in moving team platform : database is the team or one of its descendants"),
+		},
+		{
+			name:     "Testing move under the team itself",
+			team:     "backend",
+			parent:   "Backend",
+			expected: fmt.Errorf("Error in moving team backend : Backend is the team or one of its descendants"),
+		},
+		{
+			name:     "Testing unknown parent",
+			team:     "backend",
+			parent:   "missing",
+			expected: fmt.Errorf("Error in moving team backend : parent team missing not found"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client and answer the update with the body it receives
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodGet, fmt.Sprintf("%s/orgs/%s/teams", DefaultAPIURL, TestOrg), http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(treeResponse)),
+			})
+			var body string
+			service := NewService(clientFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodPatch {
+					return mockClient.Do(req)
+				}
+				data, _ := ioutil.ReadAll(req.Body)
+				body = string(data)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 2}`)),
+				}, nil
+			}), TestOrg, "")
+
+			_, got := service.MoveTeam(context.Background(), tt.team, tt.parent)
+			if got != nil && tt.expected != nil {
+				if got.Error() != tt.expected.Error() {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			} else {
+				if got != tt.expected {
+					t.Errorf("wanted %v, got %v", tt.expected, got.Error())
+				}
+			}
+			if body != tt.expectedBody {
+				t.Errorf("wanted body %s, got %s", tt.expectedBody, body)
+			}
+		})
+	}
+}
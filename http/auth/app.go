@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+)
+
+// Lifetimes of the tokens involved in GitHub App authentication
+const (
+	// jwtLifetime stays below the ten minutes GitHub accepts for App JWTs
+	jwtLifetime = 9 * time.Minute
+	// jwtClockSkew backdates the JWT to tolerate clocks running ahead of GitHub
+	jwtClockSkew = time.Minute
+	// refreshMargin is how long before expiry an installation token is replaced
+	refreshMargin = 5 * time.Minute
+)
+
+// AppTokenSource is a TokenSource that mints installation access tokens for a GitHub App
+// and caches them until shortly before they expire
+type AppTokenSource struct {
+	client         httpclient.Client
+	apiURL         string
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+// NewAppTokenSource creates an AppTokenSource for the installation of the App identified by
+// appID, privateKeyPEM is the private key generated for the App. Tokens are requested
+// through client from apiURL, the root of the GitHub API
+func NewAppTokenSource(client httpclient.Client, apiURL string, appID, installationID int64, privateKeyPEM []byte) (*AppTokenSource, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &AppTokenSource{
+		client:         client,
+		apiURL:         strings.TrimSuffix(apiURL, "/"),
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		now:            time.Now,
+	}, nil
+}
+
+// Token returns the cached installation token, minting a new one when it is about to expire
+func (s *AppTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Add(refreshMargin).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, expiresAt, err := s.mintInstallationToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiresAt = token, expiresAt
+	return token, nil
+}
+
+// mintInstallationToken exchanges an App JWT for an installation access token
+func (s *AppTokenSource) mintInstallationToken(ctx context.Context) (string, time.Time, error) {
+	jwt, err := s.appJWT()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.apiURL, s.installationID)
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Error occurred while creating installation token request " + err.Error())
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	response, err := s.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Error occurred while requesting installation token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("Error in minting installation token for installation %d: status %d", s.installationID, response.StatusCode)
+	}
+
+	var tokenResponse struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Error in reading installation token response %s", err.Error())
+	}
+	// The decoding error is not wrapped since it could quote the token
+	if err := json.Unmarshal(body, &tokenResponse); err != nil || tokenResponse.Token == "" {
+		return "", time.Time{}, fmt.Errorf("Error in unmarshalling installation token response")
+	}
+	return tokenResponse.Token, tokenResponse.ExpiresAt, nil
+}
+
+// appJWT signs the RS256 JSON Web Token that authenticates as the App itself
+func (s *AppTokenSource) appJWT() (string, error) {
+	now := s.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(struct {
+		IssuedAt  int64  `json:"iat"`
+		ExpiresAt int64  `json:"exp"`
+		Issuer    string `json:"iss"`
+	}{
+		IssuedAt:  now.Add(-jwtClockSkew).Unix(),
+		ExpiresAt: now.Add(jwtLifetime).Unix(),
+		Issuer:    strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("Error in signing App JWT %s", err.Error())
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey reads an RSA private key in PKCS#1 or PKCS#8 PEM encoding
+func parsePrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(bytes.TrimSpace(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("Error in parsing App private key, no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error in parsing App private key %s", err.Error())
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Error in parsing App private key, expected an RSA key got %T", parsed)
+	}
+	return key, nil
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http/net"
+)
+
+// verifyJWT checks the RS256 signature of jwt and returns its claims
+func verifyJWT(t *testing.T, key *rsa.PublicKey, jwt string) map[string]interface{} {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("wanted a JWT with 3 parts, got %q", jwt)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("wanted a valid signature, got %v", err)
+	}
+
+	claims := map[string]interface{}{}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(payload, &claims)
+	return claims
+}
+
+// Test used to test minting, caching and refreshing of installation tokens
+func TestAppTokenSource_Token(t *testing.T) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	now := time.Now()
+
+	// Local fake of the installation token endpoint
+	minted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		claims := verifyJWT(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if claims["iss"] != "1234" {
+			t.Errorf("wanted issuer 1234, got %v", claims["iss"])
+		}
+		minted++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "ghs_token%d", "expires_at": %q}`, minted, now.Add(time.Hour).UTC().Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	source, err := NewAppTokenSource(net.Client{}, server.URL, 1234, 42, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.now = func() time.Time { return now }
+
+	// Create your table test
+	var tests = []struct {
+		name          string
+		elapsed       time.Duration
+		expectedToken string
+	}{
+		{
+			name:          "First call mints a token",
+			elapsed:       0,
+			expectedToken: "ghs_token1",
+		},
+		{
+			name:          "Valid token is cached",
+			elapsed:       30 * time.Minute,
+			expectedToken: "ghs_token1",
+		},
+		{
+			name:          "Token about to expire is refreshed",
+			elapsed:       58 * time.Minute,
+			expectedToken: "ghs_token2",
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			source.now = func() time.Time { return now.Add(tt.elapsed) }
+			got, err := source.Token(context.Background())
+			if err != nil {
+				t.Fatalf("wanted no error, got %v", err)
+			}
+			if got != tt.expectedToken {
+				t.Errorf("wanted %v, got %v", tt.expectedToken, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+)
+
+// TokenSource provides the token used to authenticate against GitHub
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// Client is a http.Client that authenticates every request with a token from a TokenSource
+type Client struct {
+	client httpclient.Client
+	source TokenSource
+}
+
+// NewClient wraps client so requests carry the token of source
+func NewClient(client httpclient.Client, source TokenSource) *Client {
+	return &Client{
+		client: client,
+		source: source,
+	}
+}
+
+// Do sets the Authorization header of a copy of the request and sends it
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	token, err := c.source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("Error in getting auth token: %w", err)
+	}
+
+	authReq := req.Clone(req.Context())
+	authReq.Header.Set("Authorization", "Bearer "+token)
+	return c.client.Do(authReq)
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// clientFunc adapts a function to the http.Client interface
+type clientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls the function
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// mockBody converts a string into the body of a mocked response
+func mockBody(body string) io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(body))
+}
+
+// Test used to test that the Client sets the Authorization header
+func TestClient_Do(t *testing.T) {
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	// The mock Client plays both the token endpoint and the GitHub API
+	teamsURL := "https://api.github.com/orgs/HybriStratus/teams"
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodPost, "https://api.github.com/app/installations/42/access_tokens", http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       mockBody(fmt.Sprintf(`{"token": "ghs_secret", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))),
+	})
+	mockClient.SetResponses(http.MethodGet, teamsURL, http.Response{StatusCode: http.StatusOK})
+
+	source, err := NewAppTokenSource(mockClient, "https://api.github.com/", 1234, 42, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var authorization string
+	client := NewClient(clientFunc(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return mockClient.Do(req)
+	}), source)
+
+	req, _ := http.NewRequest(http.MethodGet, teamsURL, nil)
+	req.Header.Set("Authorization", "Bearer stale")
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	if authorization != "Bearer ghs_secret" {
+		t.Errorf("wanted the installation token, got %q", authorization)
+	}
+	if req.Header.Get("Authorization") != "Bearer stale" {
+		t.Errorf("wanted the original request untouched")
+	}
+
+	if _, err := NewAppTokenSource(mockClient, "", 1, 1, []byte("not a key")); err == nil {
+		t.Errorf("wanted an error for an invalid private key")
+	}
+}
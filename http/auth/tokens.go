@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The providers below never quote a token or the output it was read from in their
+// errors, so errors can be logged safely
+
+// StaticToken is a TokenSource that always returns the same token
+type StaticToken string
+
+// Token returns the static token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// EnvToken is a TokenSource that reads the token from an environment variable on every
+// call, an unset variable yields no token and requests are sent unauthenticated
+type EnvToken string
+
+// Token returns the current value of the environment variable
+func (name EnvToken) Token(ctx context.Context) (string, error) {
+	return strings.TrimSpace(os.Getenv(string(name))), nil
+}
+
+// FileTokenSource is a TokenSource that reads the token from a file and reads it again
+// whenever the file changes, so tokens can be rotated without a restart
+type FileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenSource creates a FileTokenSource for the file at path
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// Token returns the token in the file, reading it again if it changed since the last call
+func (s *FileTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("Error in reading token file %s", err.Error())
+	}
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, nil
+	}
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("Error in reading token file %s", err.Error())
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("Error in reading token file %s, the file is empty", s.path)
+	}
+	s.token, s.modTime, s.size = token, info.ModTime(), info.Size()
+	return token, nil
+}
+
+// CommandTokenSource is a TokenSource that runs an external command, such as a credential
+// helper, and uses its trimmed standard output as the token
+type CommandTokenSource struct {
+	name string
+	args []string
+	// TTL is how long a token is reused before the command runs again, zero runs it on every call
+	TTL time.Duration
+
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+// NewCommandTokenSource creates a CommandTokenSource running name with args
+func NewCommandTokenSource(name string, args ...string) *CommandTokenSource {
+	return &CommandTokenSource{
+		name: name,
+		args: args,
+		now:  time.Now,
+	}
+}
+
+// Token runs the command unless the last token is still within its TTL
+func (s *CommandTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.TTL > 0 && s.now().Before(s.fetchedAt.Add(s.TTL)) {
+		return s.token, nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.name, s.args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = ioutil.Discard
+	// Only the exit status is reported, the output may hold the token
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("Error in running token command %s: %s", s.name, err.Error())
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("Error in running token command %s, no token was printed", s.name)
+	}
+	s.token, s.fetchedAt = token, s.now()
+	return token, nil
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test used to test the built-in token providers
+func TestTokenSources(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	ioutil.WriteFile(tokenFile, []byte("ghp_file\n"), 0600)
+	os.Setenv("TEST_GITHUB_TOKEN", "ghp_env")
+	defer os.Unsetenv("TEST_GITHUB_TOKEN")
+
+	// Create your table test
+	var tests = []struct {
+		name          string
+		source        TokenSource
+		expectedToken string
+		expectedError bool
+	}{
+		{
+			name:          "Static token",
+			source:        StaticToken("ghp_static"),
+			expectedToken: "ghp_static",
+		},
+		{
+			name:          "Environment variable",
+			source:        EnvToken("TEST_GITHUB_TOKEN"),
+			expectedToken: "ghp_env",
+		},
+		{
+			name:          "Unset environment variable",
+			source:        EnvToken("TEST_GITHUB_TOKEN_UNSET"),
+			expectedToken: "",
+		},
+		{
+			name:          "File",
+			source:        NewFileTokenSource(tokenFile),
+			expectedToken: "ghp_file",
+		},
+		{
+			name:          "Missing file",
+			source:        NewFileTokenSource(filepath.Join(dir, "missing")),
+			expectedError: true,
+		},
+		{
+			name:          "Command",
+			source:        NewCommandTokenSource("sh", "-c", "echo ghp_command"),
+			expectedToken: "ghp_command",
+		},
+		{
+			name:          "Failing command",
+			source:        NewCommandTokenSource("sh", "-c", "echo ghp_leaked; echo ghp_leaked >&2; exit 3"),
+			expectedError: true,
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := tt.source.Token(context.Background())
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("wanted error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil && strings.Contains(err.Error(), "ghp_") {
+				t.Errorf("wanted an error without the token, got %v", err)
+			}
+			if got != tt.expectedToken {
+				t.Errorf("wanted %v, got %v", tt.expectedToken, got)
+			}
+		})
+	}
+}
+
+// Test used to test that a rotated token file is read again
+func TestFileTokenSource_Rotation(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	ioutil.WriteFile(tokenFile, []byte("ghp_old"), 0600)
+
+	source := NewFileTokenSource(tokenFile)
+	if got, _ := source.Token(context.Background()); got != "ghp_old" {
+		t.Fatalf("wanted ghp_old, got %v", got)
+	}
+
+	ioutil.WriteFile(tokenFile, []byte("ghp_rotated"), 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(tokenFile, later, later)
+	if got, _ := source.Token(context.Background()); got != "ghp_rotated" {
+		t.Errorf("wanted ghp_rotated, got %v", got)
+	}
+}
+
+// Test used to test that command tokens are reused within their TTL
+func TestCommandTokenSource_TTL(t *testing.T) {
+
+	now := time.Now()
+	source := NewCommandTokenSource("sh", "-c", "date +%N")
+	source.TTL = time.Minute
+	source.now = func() time.Time { return now }
+
+	first, _ := source.Token(context.Background())
+	second, _ := source.Token(context.Background())
+	if first == "" || first != second {
+		t.Errorf("wanted the token to be reused, got %v and %v", first, second)
+	}
+
+	now = now.Add(2 * time.Minute)
+	third, _ := source.Token(context.Background())
+	if third == first {
+		t.Errorf("wanted a new token after the TTL, got %v", third)
+	}
+}
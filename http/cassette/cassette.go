@@ -0,0 +1,135 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ScrubbedHeaders are left out of the recorded requests and responses so cassettes
+// can be committed without leaking credentials
+var ScrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// ScrubbedFields are the top-level fields of JSON response bodies whose value is replaced
+// by Scrubbed, such as the token of a GitHub App installation access token
+var ScrubbedFields = []string{"token"}
+
+// Scrubbed replaces the values of the ScrubbedFields in the recorded responses
+const Scrubbed = "REDACTED"
+
+// Cassette is a list of recorded API exchanges
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it got
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. A JSON body is kept in Body so cassettes stay
+// readable, any other body in Text
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// Response is a recorded response, its body is kept like the one of the Request
+type Response struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	Text       string          `json:"text,omitempty"`
+}
+
+// encodeBody splits a body into its JSON or text form
+func encodeBody(body []byte) (json.RawMessage, string) {
+	var compact bytes.Buffer
+	if len(body) > 0 && json.Compact(&compact, body) == nil {
+		return compact.Bytes(), ""
+	}
+	return nil, string(body)
+}
+
+// decodeBody joins the JSON or text form of a body
+func decodeBody(raw json.RawMessage, text string) []byte {
+	if len(raw) > 0 {
+		return raw
+	}
+	return []byte(text)
+}
+
+// Load reads the cassette at path
+func Load(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error in reading cassette: %w", err)
+	}
+	cassette := &Cassette{}
+	err = json.Unmarshal(data, cassette)
+	if err != nil {
+		return nil, fmt.Errorf("Error in unmarshalling cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// Save writes the cassette to path, creating its directory
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error in marshalling cassette: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("Error in writing cassette: %w", err)
+	}
+	err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("Error in writing cassette: %w", err)
+	}
+	return nil
+}
+
+// scrub copies header without the ScrubbedHeaders
+func scrub(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	scrubbed := header.Clone()
+	for _, key := range ScrubbedHeaders {
+		scrubbed.Del(key)
+	}
+	return scrubbed
+}
+
+// scrubBody copies a JSON object body with the ScrubbedFields replaced, any other body is
+// returned as is
+func scrubBody(body []byte) []byte {
+	fields := map[string]json.RawMessage{}
+	if json.Unmarshal(body, &fields) != nil {
+		return body
+	}
+	scrubbed := false
+	for _, field := range ScrubbedFields {
+		if _, ok := fields[field]; ok {
+			fields[field], _ = json.Marshal(Scrubbed)
+			scrubbed = true
+		}
+	}
+	if !scrubbed {
+		return body
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return data
+}
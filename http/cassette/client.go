@@ -0,0 +1,185 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+)
+
+// ErrNoInteraction is returned when the cassette has no unused interaction matching a request
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Recorder is a http.Client that sends the requests through client and records them
+// with their responses, without the ScrubbedHeaders nor the values of the ScrubbedFields,
+// to be saved as a cassette. The caller still gets the responses as they were sent
+type Recorder struct {
+	client httpclient.Client
+	path   string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder wraps client, recording into the cassette at path once Save is called
+func NewRecorder(client httpclient.Client, path string) *Recorder {
+	return &Recorder{client: client, path: path}
+}
+
+// Do sends the request and records it with its response. Failed requests are not recorded
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	response, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	var responseBody []byte
+	if response.Body != nil {
+		responseBody, err = ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+	}
+
+	interaction := Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Header: scrub(req.Header)},
+		Response: Response{StatusCode: response.StatusCode, Header: scrub(response.Header)},
+	}
+	interaction.Request.Body, interaction.Request.Text = encodeBody(requestBody)
+	interaction.Response.Body, interaction.Response.Text = encodeBody(scrubBody(responseBody))
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return response, nil
+}
+
+// Save writes the interactions recorded so far to the cassette
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// Replayer is a http.Client answering the requests with the responses of a cassette
+// instead of sending them. A request is answered by the first unused interaction with
+// the same method, path, query and body, so repeated requests replay in recorded order.
+// The host is ignored, letting cassettes recorded against one API root replay against another
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the cassette at path
+func NewReplayer(path string) (*Replayer, error) {
+	cassette, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// Do answers the request with the recorded response, it fails with ErrNoInteraction
+// when the cassette has none left for it
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+		recorded := interaction.Response
+		responseBody := decodeBody(recorded.Body, recorded.Text)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(responseBody)),
+			ContentLength: int64(len(responseBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("Error in replaying %s %s: %w", req.Method, req.URL, ErrNoInteraction)
+}
+
+// Unused returns the interactions of the cassette no request was answered with
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// matches tells whether the request has the method, path, query and body of the recorded one.
+// The query parameters may come in any order and JSON bodies may differ in spacing and key order
+func matches(recorded Request, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil || recordedURL.EscapedPath() != req.URL.EscapedPath() {
+		return false
+	}
+	if !reflect.DeepEqual(normalizeQuery(recordedURL.Query()), normalizeQuery(req.URL.Query())) {
+		return false
+	}
+
+	recordedBody := decodeBody(recorded.Body, recorded.Text)
+	var want, got interface{}
+	if json.Unmarshal(recordedBody, &want) == nil && json.Unmarshal(body, &got) == nil {
+		return reflect.DeepEqual(want, got)
+	}
+	return bytes.Equal(recordedBody, body)
+}
+
+// normalizeQuery maps an empty query to nil so it equals a missing one
+func normalizeQuery(query url.Values) url.Values {
+	if len(query) == 0 {
+		return nil
+	}
+	return query
+}
@@ -0,0 +1,257 @@
+package cassette
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+	"github.com/HybriStratus/test-github-groups/http/auth"
+	"github.com/HybriStratus/test-github-groups/http/fake"
+	"github.com/HybriStratus/test-github-groups/http/mock"
+	"github.com/HybriStratus/test-github-groups/http/net"
+)
+
+// Test used to test recording exchanges with a fake GitHub and replaying them without it
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassettes", "teams.json")
+	ctx := context.Background()
+
+	// Record against the fake GitHub with a token
+	server := fake.NewServer()
+	server.SetToken("ghp_secret")
+	server.AddOrg(groups.TestOrg)
+	recorder := NewRecorder(net.Client{}, path)
+	service := groups.NewService(recorder, groups.TestOrg, server.URL)
+	service.TokenSource = auth.StaticToken("ghp_secret")
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		if _, err := service.CreateTeam(ctx, &groups.Team{Name: name, Description: "The " + name + " team"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := service.ListTeams(ctx, &groups.ListOptions{PerPage: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ghp_secret") {
+		t.Errorf("wanted the token scrubbed from the cassette, got %s", data)
+	}
+
+	// Replay without the server nor a token
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service = groups.NewService(replayer, groups.TestOrg, "")
+	service.TokenSource = nil
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		run      func() (string, error)
+		expected string
+		err      error
+	}{
+		{
+			name: "Testing replay of a created team",
+			run: func() (string, error) {
+				details, err := service.CreateTeam(ctx, &groups.Team{Description: "The beta team", Name: "beta"})
+				if err != nil {
+					return "", err
+				}
+				return details.Slug, nil
+			},
+			expected: "beta",
+		},
+		{
+			name: "Testing replay of a paginated listing",
+			run: func() (string, error) {
+				teams, err := service.ListTeams(ctx, &groups.ListOptions{PerPage: 2})
+				var slugs []string
+				for _, team := range teams {
+					slugs = append(slugs, team.Slug)
+				}
+				return strings.Join(slugs, " "), err
+			},
+			expected: "alpha beta gamma",
+		},
+		{
+			name: "Testing request replayed once",
+			run: func() (string, error) {
+				_, err := service.CreateTeam(ctx, &groups.Team{Name: "beta", Description: "The beta team"})
+				return "", err
+			},
+			err: ErrNoInteraction,
+		},
+		{
+			name: "Testing request with another body",
+			run: func() (string, error) {
+				_, err := service.CreateTeam(ctx, &groups.Team{Name: "alpha"})
+				return "", err
+			},
+			err: ErrNoInteraction,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.run()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("wanted %v, got %v", tt.err, err)
+			}
+			if got != tt.expected {
+				t.Errorf("wanted %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if unused := replayer.Unused(); len(unused) != 2 {
+		t.Errorf("wanted the alpha and gamma creations unused, got %d", len(unused))
+	}
+}
+
+// Test used to test that installation tokens are scrubbed from the recorded responses only
+func TestRecordScrubsTokens(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.json")
+
+	tokensURL := "https://api.github.com/app/installations/42/access_tokens"
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodPost, tokensURL, http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"token": "ghs_live", "expires_at": "2030-01-01T00:00:00Z"}`)),
+	})
+	recorder := NewRecorder(mockClient, path)
+
+	source, err := auth.NewAppTokenSource(recorder, "https://api.github.com", 1, 42, testKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "ghs_live" {
+		t.Errorf("wanted the live token returned, got %q", token)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ghs_live") || !strings.Contains(string(data), Scrubbed) {
+		t.Errorf("wanted the token scrubbed from the cassette, got %s", data)
+	}
+	if !strings.Contains(string(data), "2030-01-01T00:00:00Z") {
+		t.Errorf("wanted the other fields kept, got %s", data)
+	}
+}
+
+// testKey returns a PEM encoded RSA private key for a GitHub App
+func testKey(t *testing.T) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// Test used to test how recorded requests are matched
+func TestMatches(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		recorded Request
+		method   string
+		url      string
+		body     string
+		expected bool
+	}{
+		{
+			name:     "Testing other host",
+			recorded: Request{Method: "GET", URL: "https://api.github.com/orgs/acme/teams"},
+			method:   "GET",
+			url:      "http://127.0.0.1:8080/orgs/acme/teams",
+			expected: true,
+		},
+		{
+			name:     "Testing other method",
+			recorded: Request{Method: "GET", URL: "https://api.github.com/orgs/acme/teams"},
+			method:   "POST",
+			url:      "https://api.github.com/orgs/acme/teams",
+			expected: false,
+		},
+		{
+			name:     "Testing query in another order",
+			recorded: Request{Method: "GET", URL: "https://api.github.com/orgs/acme/teams?page=2&per_page=10"},
+			method:   "GET",
+			url:      "https://api.github.com/orgs/acme/teams?per_page=10&page=2",
+			expected: true,
+		},
+		{
+			name:     "Testing other query",
+			recorded: Request{Method: "GET", URL: "https://api.github.com/orgs/acme/teams?page=2"},
+			method:   "GET",
+			url:      "https://api.github.com/orgs/acme/teams?page=3",
+			expected: false,
+		},
+		{
+			name:     "Testing JSON body with other key order",
+			recorded: Request{Method: "POST", URL: "https://api.github.com/orgs/acme/teams", Body: []byte(`{"name":"a","privacy":"closed"}`)},
+			method:   "POST",
+			url:      "https://api.github.com/orgs/acme/teams",
+			body:     `{"privacy": "closed", "name": "a"}`,
+			expected: true,
+		},
+		{
+			name:     "Testing text body",
+			recorded: Request{Method: "POST", URL: "https://api.github.com/markdown/raw", Text: "# title"},
+			method:   "POST",
+			url:      "https://api.github.com/markdown/raw",
+			body:     "# title",
+			expected: true,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := matches(tt.recorded, req, []byte(tt.body)); got != tt.expected {
+				t.Errorf("wanted %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
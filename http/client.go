@@ -1,8 +1,10 @@
 package http
 
 import (
+	"context"
 	"io"
 	"net/http"
+	"time"
 )
 
 // A Client interface that wraps the net.http client's Do method
@@ -37,3 +39,15 @@ func NewResponse(res *http.Response) *Response {
 		TLS:              res.TLS,
 	}
 }
+
+// Sleep waits for d or until ctx is done, it returns the error of ctx in the latter case
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
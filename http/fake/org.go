@@ -0,0 +1,293 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Roles of the users invited to an organization
+var invitationRoles = []string{"admin", "direct_member", "billing_manager"}
+
+// invitationOf returns the pending invitation of the user, nil when there is none
+func (o *org) invitationOf(login string) *invitation {
+	for _, inv := range o.invitations {
+		if strings.EqualFold(inv.login, login) {
+			return inv
+		}
+	}
+	return nil
+}
+
+// invite invites the user to the organization, adding the teams to a pending invitation
+func (s *Server) invite(o *org, login, role string, teamIDs ...int) *invitation {
+	inv := o.invitationOf(login)
+	if inv == nil {
+		inv = &invitation{id: s.newID(), login: login, createdAt: time.Now().UTC().Format(time.RFC3339)}
+		o.invitations[inv.id] = inv
+	}
+	inv.role = role
+	inv.teamIDs = append(inv.teamIDs, teamIDs...)
+	return inv
+}
+
+// uninvite drops the invitation with its pending team memberships
+func (o *org) uninvite(inv *invitation) {
+	delete(o.invitations, inv.id)
+	login := strings.ToLower(inv.login)
+	for _, t := range o.teams {
+		if t.pending[login] {
+			delete(t.members, login)
+			delete(t.pending, login)
+		}
+	}
+}
+
+// join makes the user an active member of the organization and of the teams it was invited to
+func (o *org) join(login, role string) {
+	login = strings.ToLower(login)
+	o.members[login] = role
+	delete(o.collaborators, login)
+	for _, t := range o.teams {
+		delete(t.pending, login)
+	}
+	if inv := o.invitationOf(login); inv != nil {
+		delete(o.invitations, inv.id)
+	}
+}
+
+// serveOrgMembership serves /orgs/{org}/memberships/{user}
+func (s *Server) serveOrgMembership(req *request, o *org) {
+	login := strings.ToLower(req.segments[3])
+	u, ok := s.users[login]
+	if !ok {
+		req.error(http.StatusNotFound, "Not Found")
+		return
+	}
+	membershipJSON := func() map[string]interface{} {
+		state, role := "active", o.members[login]
+		if inv := o.invitationOf(login); role == "" && inv != nil {
+			state, role = "pending", "member"
+			if inv.role == "admin" {
+				role = "admin"
+			}
+		}
+		return map[string]interface{}{
+			"url":              fmt.Sprintf("%s/orgs/%s/memberships/%s", req.base, o.login, u.login),
+			"state":            state,
+			"role":             role,
+			"organization_url": fmt.Sprintf("%s/orgs/%s", req.base, o.login),
+			"user":             s.userJSON(req, u),
+		}
+	}
+	_, member := o.members[login]
+	inv := o.invitationOf(login)
+
+	switch req.Method {
+	case http.MethodGet:
+		if !member && inv == nil {
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		req.writeJSON(http.StatusOK, membershipJSON())
+
+	case http.MethodPut:
+		payload := struct {
+			Role string `json:"role"`
+		}{}
+		if !req.decode(&payload) {
+			return
+		}
+		if payload.Role == "" {
+			payload.Role = "member"
+		}
+		if payload.Role != "admin" && payload.Role != "member" {
+			req.validationFailed("Membership", "role", "invalid", "Role must be admin or member")
+			return
+		}
+		// Users outside the organization are invited and stay pending until they join
+		if member {
+			o.members[login] = payload.Role
+		} else if payload.Role == "admin" {
+			s.invite(o, u.login, "admin")
+		} else {
+			s.invite(o, u.login, "direct_member")
+		}
+		req.writeJSON(http.StatusOK, membershipJSON())
+
+	case http.MethodDelete:
+		switch {
+		case member:
+			delete(o.members, login)
+			for _, t := range o.teams {
+				delete(t.members, login)
+				delete(t.pending, login)
+			}
+		case inv != nil:
+			o.uninvite(inv)
+		default:
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		req.noContent()
+
+	default:
+		req.error(http.StatusNotFound, "Not Found")
+	}
+}
+
+// listOrgMembers serves GET /orgs/{org}/members, filtered by the role query parameter
+func (s *Server) listOrgMembers(req *request, o *org) {
+	role := req.URL.Query().Get("role")
+	if role == "" {
+		role = "all"
+	}
+	if role != "all" && role != "admin" && role != "member" {
+		req.validationFailed("Membership", "role", "invalid", "Role must be all, admin or member")
+		return
+	}
+	items := []interface{}{}
+	for _, login := range sortedKeys(o.members) {
+		if role == "all" || o.members[login] == role {
+			items = append(items, s.userJSON(req, s.users[login]))
+		}
+	}
+	req.paginate(items)
+}
+
+// listLogins answers with a page of the users in logins
+func (s *Server) listLogins(req *request, logins map[string]bool) {
+	var keys []string
+	for login := range logins {
+		keys = append(keys, login)
+	}
+	sort.Strings(keys)
+	items := []interface{}{}
+	for _, login := range keys {
+		items = append(items, s.userJSON(req, s.users[login]))
+	}
+	req.paginate(items)
+}
+
+// listInvitations answers with a page of the invitations kept by keep, oldest first
+func (s *Server) listInvitations(req *request, o *org, keep func(*invitation) bool) {
+	var ids []int
+	for id, inv := range o.invitations {
+		if keep(inv) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	items := []interface{}{}
+	for _, id := range ids {
+		items = append(items, s.invitationJSON(req, o, o.invitations[id]))
+	}
+	req.paginate(items)
+}
+
+// invitationJSON renders an invitation, the login or email it was not sent to is null
+func (s *Server) invitationJSON(req *request, o *org, inv *invitation) map[string]interface{} {
+	rendered := map[string]interface{}{
+		"id":                   inv.id,
+		"login":                nil,
+		"email":                nil,
+		"role":                 inv.role,
+		"created_at":           inv.createdAt,
+		"inviter":              nil,
+		"team_count":           len(inv.teamIDs),
+		"invitation_teams_url": fmt.Sprintf("%s/organizations/%d/invitations/%d/teams", req.base, o.id, inv.id),
+	}
+	if inv.login != "" {
+		rendered["login"] = inv.login
+	}
+	if inv.email != "" {
+		rendered["email"] = inv.email
+	}
+	return rendered
+}
+
+// createInvitation serves POST /orgs/{org}/invitations
+func (s *Server) createInvitation(req *request, o *org) {
+	payload := struct {
+		InviteeID int    `json:"invitee_id"`
+		Email     string `json:"email"`
+		Role      string `json:"role"`
+		TeamIDs   []int  `json:"team_ids"`
+	}{}
+	if !req.decode(&payload) {
+		return
+	}
+	if (payload.InviteeID == 0) == (payload.Email == "") {
+		req.validationFailed("OrganizationInvitation", "invitee_id", "invalid", "Exactly one of invitee_id or email is required")
+		return
+	}
+	if payload.Role == "" {
+		payload.Role = "direct_member"
+	}
+	valid := false
+	for _, role := range invitationRoles {
+		valid = valid || role == payload.Role
+	}
+	if !valid {
+		req.validationFailed("OrganizationInvitation", "role", "invalid", "Role must be admin, direct_member or billing_manager")
+		return
+	}
+	for _, id := range payload.TeamIDs {
+		if _, ok := o.teams[id]; !ok {
+			req.validationFailed("OrganizationInvitation", "team_ids", "invalid", fmt.Sprintf("Team %d does not exist in the organization", id))
+			return
+		}
+	}
+
+	if payload.Email != "" {
+		inv := &invitation{
+			id:        s.newID(),
+			email:     payload.Email,
+			role:      payload.Role,
+			teamIDs:   payload.TeamIDs,
+			createdAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		o.invitations[inv.id] = inv
+		req.writeJSON(http.StatusCreated, s.invitationJSON(req, o, inv))
+		return
+	}
+
+	var invitee *user
+	for _, u := range s.users {
+		if u.id == payload.InviteeID {
+			invitee = u
+		}
+	}
+	if invitee == nil {
+		req.validationFailed("OrganizationInvitation", "invitee_id", "invalid", "Invitee does not exist")
+		return
+	}
+	if _, ok := o.members[strings.ToLower(invitee.login)]; ok {
+		req.validationFailed("OrganizationInvitation", "invitee_id", "already_exists", "Invitee is already a part of this organization")
+		return
+	}
+	inv := s.invite(o, invitee.login, payload.Role, payload.TeamIDs...)
+	for _, id := range payload.TeamIDs {
+		t := o.teams[id]
+		if _, ok := t.members[strings.ToLower(invitee.login)]; !ok {
+			t.members[strings.ToLower(invitee.login)] = "member"
+		}
+		t.pending[strings.ToLower(invitee.login)] = true
+	}
+	req.writeJSON(http.StatusCreated, s.invitationJSON(req, o, inv))
+}
+
+// cancelInvitation serves DELETE /orgs/{org}/invitations/{id}
+func (s *Server) cancelInvitation(req *request, o *org) {
+	id, err := strconv.Atoi(req.segments[3])
+	inv, ok := o.invitations[id]
+	if err != nil || !ok {
+		req.error(http.StatusNotFound, "Not Found")
+		return
+	}
+	o.uninvite(inv)
+	req.noContent()
+}
@@ -0,0 +1,398 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pagination of the list endpoints, as enforced by GitHub
+const (
+	DefaultPerPage = 30
+	MaxPerPage     = 100
+)
+
+// Server is an in-memory fake of the GitHub teams, memberships, repositories and
+// organization endpoints served over HTTP. Point a groups.Service at URL to test
+// it offline. Organizations, users and repositories are seeded with the Add methods,
+// everything else is created through the API
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	nextID    int
+	requests  int
+	token     string
+	rateLimit int
+	rateUsed  int
+	rateReset time.Time
+	orgs      map[string]*org
+	users     map[string]*user
+	repos     map[string]*repo
+}
+
+type user struct {
+	id    int
+	login string
+}
+
+type repo struct {
+	id      int
+	owner   string
+	name    string
+	private bool
+}
+
+type team struct {
+	id          int
+	name        string
+	slug        string
+	description string
+	privacy     string
+	parentID    int
+	// members maps lowercase logins to their role, maintainer or member
+	members map[string]string
+	pending map[string]bool
+	// repos maps lowercase full names to the permission of the team
+	repos map[string]string
+}
+
+type invitation struct {
+	id        int
+	login     string
+	email     string
+	role      string
+	teamIDs   []int
+	createdAt string
+}
+
+type org struct {
+	id            int
+	login         string
+	teams         map[int]*team
+	members       map[string]string
+	collaborators map[string]bool
+	invitations   map[int]*invitation
+}
+
+// NewServer starts a Server without any organization, close it when done
+func NewServer() *Server {
+	s := &Server{
+		orgs:  map[string]*org{},
+		users: map[string]*user{},
+		repos: map[string]*repo{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetToken makes every request require the token, as a Bearer or token Authorization
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// SetRateLimit allows limit requests per hour, further requests are rejected with 403
+// until ResetRateLimit. Zero disables the limit
+func (s *Server) SetRateLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+	s.rateUsed = 0
+	s.rateReset = time.Now().Add(time.Hour)
+}
+
+// ResetRateLimit starts a new rate limit window
+func (s *Server) ResetRateLimit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateUsed = 0
+	s.rateReset = time.Now().Add(time.Hour)
+}
+
+// Requests returns the number of requests served
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// AddOrg creates an organization
+func (s *Server) AddOrg(login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgs[strings.ToLower(login)] = &org{
+		id:            s.newID(),
+		login:         login,
+		teams:         map[int]*team{},
+		members:       map[string]string{},
+		collaborators: map[string]bool{},
+		invitations:   map[int]*invitation{},
+	}
+}
+
+// AddUser creates a user and returns its ID
+func (s *Server) AddUser(login string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(login).id
+}
+
+// AddOrgMember creates the user if needed and makes it a member of the organization with role, admin or member.
+// It accepts the pending invitation of the user as well, activating its team memberships
+func (s *Server) AddOrgMember(orgLogin, login, role string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addUser(login)
+	s.orgs[strings.ToLower(orgLogin)].join(login, role)
+}
+
+// AddOutsideCollaborator creates the user if needed and gives it access to repositories of the organization
+func (s *Server) AddOutsideCollaborator(orgLogin, login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addUser(login)
+	s.orgs[strings.ToLower(orgLogin)].collaborators[strings.ToLower(login)] = true
+}
+
+// AddRepo creates the repository owner/name
+func (s *Server) AddRepo(owner, name string, private bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos[strings.ToLower(owner+"/"+name)] = &repo{id: s.newID(), owner: owner, name: name, private: private}
+}
+
+// newID returns the next ID shared by every resource
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+// addUser creates the user unless it exists
+func (s *Server) addUser(login string) *user {
+	key := strings.ToLower(login)
+	if u, ok := s.users[key]; ok {
+		return u
+	}
+	u := &user{id: s.newID(), login: login}
+	s.users[key] = u
+	return u
+}
+
+// request is a request being served with the unescaped segments of its path
+type request struct {
+	*http.Request
+	w        http.ResponseWriter
+	segments []string
+	base     string
+}
+
+// serveHTTP checks the credentials and rate limit of a request and routes it
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-GitHub-Request-Id", fmt.Sprintf("FAKE:%d", s.requests))
+
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			unescaped = segment
+		}
+		segments = append(segments, unescaped)
+	}
+	req := &request{Request: r, w: w, segments: segments, base: "http://" + r.Host}
+
+	if s.token != "" {
+		auth := r.Header.Get("Authorization")
+		if auth != "Bearer "+s.token && auth != "token "+s.token {
+			req.error(http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+	}
+	if s.rateLimit > 0 {
+		exceeded := s.rateUsed >= s.rateLimit
+		if !exceeded {
+			s.rateUsed++
+		}
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.rateLimit-s.rateUsed))
+		w.Header().Set("X-RateLimit-Used", strconv.Itoa(s.rateUsed))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.rateReset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		if exceeded {
+			req.error(http.StatusForbidden, "API rate limit exceeded")
+			return
+		}
+	}
+
+	switch {
+	case req.match("users", "*"):
+		s.getUser(req)
+	case len(segments) >= 2 && segments[0] == "orgs":
+		o, ok := s.orgs[strings.ToLower(segments[1])]
+		if !ok {
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		s.serveOrg(req, o)
+	default:
+		req.error(http.StatusNotFound, "Not Found")
+	}
+}
+
+// match tells whether the path has exactly the segments, "*" matches any segment
+func (req *request) match(segments ...string) bool {
+	if len(segments) != len(req.segments) {
+		return false
+	}
+	for i, segment := range segments {
+		if segment != "*" && segment != req.segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldError is a field-level validation error
+type fieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message,omitempty"`
+}
+
+// writeJSON answers with status and v as JSON
+func (req *request) writeJSON(status int, v interface{}) {
+	req.w.WriteHeader(status)
+	json.NewEncoder(req.w).Encode(v)
+}
+
+// noContent answers with 204
+func (req *request) noContent() {
+	req.w.Header().Del("Content-Type")
+	req.w.WriteHeader(http.StatusNoContent)
+}
+
+// error answers with status and a GitHub error body
+func (req *request) error(status int, message string, errs ...fieldError) {
+	body := map[string]interface{}{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	}
+	if len(errs) > 0 {
+		body["errors"] = errs
+	}
+	req.writeJSON(status, body)
+}
+
+// validationFailed answers with 422 for a single invalid field
+func (req *request) validationFailed(resource, field, code, message string) {
+	req.error(http.StatusUnprocessableEntity, "Validation Failed", fieldError{Resource: resource, Field: field, Code: code, Message: message})
+}
+
+// decode reads the JSON body into v, answering with 400 when it is malformed
+func (req *request) decode(v interface{}) bool {
+	if req.Body == nil || req.ContentLength == 0 {
+		return true
+	}
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		req.error(http.StatusBadRequest, "Problems parsing JSON")
+		return false
+	}
+	return true
+}
+
+// paginate answers with the page of items asked by the per_page and page query
+// parameters, linking the other pages in the Link header like GitHub does
+func (req *request) paginate(items []interface{}) {
+	query := req.URL.Query()
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	last := (len(items) + perPage - 1) / perPage
+	if last == 0 {
+		last = 1
+	}
+	link := func(page int, rel string) string {
+		query.Set("page", strconv.Itoa(page))
+		return fmt.Sprintf(`<%s%s?%s>; rel="%s"`, req.base, req.URL.Path, query.Encode(), rel)
+	}
+	var links []string
+	if page < last {
+		links = append(links, link(page+1, "next"), link(last, "last"))
+	}
+	if page > 1 {
+		links = append(links, link(1, "first"), link(page-1, "prev"))
+	}
+	if len(links) > 0 {
+		req.w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	req.writeJSON(http.StatusOK, items[start:end])
+}
+
+// getUser serves GET /users/{login}
+func (s *Server) getUser(req *request) {
+	if req.Method != http.MethodGet {
+		req.error(http.StatusNotFound, "Not Found")
+		return
+	}
+	u, ok := s.users[strings.ToLower(req.segments[1])]
+	if !ok {
+		req.error(http.StatusNotFound, "Not Found")
+		return
+	}
+	req.writeJSON(http.StatusOK, s.userJSON(req, u))
+}
+
+// userJSON renders a user
+func (s *Server) userJSON(req *request, u *user) map[string]interface{} {
+	return map[string]interface{}{
+		"login":      u.login,
+		"id":         u.id,
+		"node_id":    fmt.Sprintf("U_%d", u.id),
+		"url":        fmt.Sprintf("%s/users/%s", req.base, u.login),
+		"html_url":   fmt.Sprintf("https://github.com/%s", u.login),
+		"type":       "User",
+		"site_admin": false,
+	}
+}
+
+// sortedKeys returns the keys of m in order, so listings are stable
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,332 @@
+package fake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// do sends a request to the server and returns the response with its body
+func do(t *testing.T, s *Server, method, path, body string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	response, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	bytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response, string(bytes)
+}
+
+// newTestServer starts a server with an organization, two members, an outsider and a repository
+func newTestServer(t *testing.T) *Server {
+	s := NewServer()
+	t.Cleanup(s.Close)
+	s.AddOrg("acme")
+	s.AddOrgMember("acme", "alice", "admin")
+	s.AddOrgMember("acme", "bob", "member")
+	s.AddUser("carol")
+	s.AddRepo("acme", "api", true)
+	return s
+}
+
+// TestServer tests the status codes and bodies of a sequence of requests sharing state
+func TestServer(t *testing.T) {
+	s := newTestServer(t)
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		header         http.Header
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Testing create team",
+			method:         http.MethodPost,
+			path:           "/orgs/acme/teams",
+			body:           `{"name": "Platform Team", "maintainers": ["alice"], "repo_names": ["acme/api"]}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"slug":"platform-team"`,
+		},
+		{
+			name:           "Testing create team with a taken name",
+			method:         http.MethodPost,
+			path:           "/orgs/acme/teams",
+			body:           `{"name": "platform team"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `"code":"already_exists"`,
+		},
+		{
+			name:           "Testing create team without name",
+			method:         http.MethodPost,
+			path:           "/orgs/acme/teams",
+			body:           `{"description": "nameless"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `"code":"missing_field"`,
+		},
+		{
+			name:           "Testing create nested team",
+			method:         http.MethodPost,
+			path:           "/orgs/acme/teams",
+			body:           `{"name": "backend", "parent_team_id": 6}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"privacy":"closed"`,
+		},
+		{
+			name:           "Testing nesting a team under its child",
+			method:         http.MethodPatch,
+			path:           "/orgs/acme/teams/platform-team",
+			body:           `{"parent_team_id": 7}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Testing get team",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/platform-team",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"members_count":1`,
+		},
+		{
+			name:           "Testing list child teams",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/platform-team/teams",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"slug":"backend"`,
+		},
+		{
+			name:           "Testing add an outsider to a team",
+			method:         http.MethodPut,
+			path:           "/orgs/acme/teams/backend/memberships/carol",
+			body:           `{"role": "member"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"state":"pending"`,
+		},
+		{
+			name:           "Testing pending members are not listed",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/backend/members",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:           "Testing team invitations",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/backend/invitations",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"login":"carol"`,
+		},
+		{
+			name:           "Testing invalid repository permission",
+			method:         http.MethodPut,
+			path:           "/orgs/acme/teams/platform-team/repos/acme/api",
+			body:           `{"permission": "write"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Testing check repository permission without media type",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/platform-team/repos/acme/api",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Testing check repository permission",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/platform-team/repos/acme/api",
+			header:         http.Header{"Accept": {repositoryMediaType}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"role_name":"pull"`,
+		},
+		{
+			name:           "Testing rename team",
+			method:         http.MethodPatch,
+			path:           "/orgs/acme/teams/platform-team",
+			body:           `{"name": "Platform"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"slug":"platform"`,
+		},
+		{
+			name:           "Testing old slug after rename",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/platform-team",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Testing delete team with its children",
+			method:         http.MethodDelete,
+			path:           "/orgs/acme/teams/platform",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Testing child team is deleted",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/teams/backend",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Testing invitations outlive deleted teams",
+			method:         http.MethodGet,
+			path:           "/orgs/acme/invitations",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"team_count":0`,
+		},
+		{
+			name:           "Testing unknown organization",
+			method:         http.MethodGet,
+			path:           "/orgs/other/teams",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Testing malformed body",
+			method:         http.MethodPost,
+			path:           "/orgs/acme/teams",
+			body:           `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, body := do(t, s, tt.method, tt.path, tt.body, tt.header)
+			if response.StatusCode != tt.expectedStatus {
+				t.Errorf("wanted %d, got %d: %s", tt.expectedStatus, response.StatusCode, body)
+			}
+			if !strings.Contains(body, tt.expectedBody) {
+				t.Errorf("wanted body containing %s, got %s", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+// TestServerPagination tests the pages and Link header of the listings
+func TestServerPagination(t *testing.T) {
+	s := newTestServer(t)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		do(t, s, http.MethodPost, "/orgs/acme/teams", `{"name": "`+name+`"}`, nil)
+	}
+
+	// Create your table test
+	tests := []struct {
+		name          string
+		query         string
+		expectedSlugs string
+		expectedLinks []string
+	}{
+		{
+			name:          "Testing default page size",
+			query:         "",
+			expectedSlugs: "a b c d e",
+		},
+		{
+			name:          "Testing first page",
+			query:         "?per_page=2",
+			expectedSlugs: "a b",
+			expectedLinks: []string{`page=2&per_page=2>; rel="next"`, `page=3&per_page=2>; rel="last"`},
+		},
+		{
+			name:          "Testing last page",
+			query:         "?per_page=2&page=3",
+			expectedSlugs: "e",
+			expectedLinks: []string{`page=1&per_page=2>; rel="first"`, `page=2&per_page=2>; rel="prev"`},
+		},
+		{
+			name:          "Testing page past the end",
+			query:         "?per_page=2&page=9",
+			expectedSlugs: "",
+			expectedLinks: []string{`page=1&per_page=2>; rel="first"`},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, body := do(t, s, http.MethodGet, "/orgs/acme/teams"+tt.query, "", nil)
+			var teams []struct {
+				Slug string `json:"slug"`
+			}
+			if err := json.Unmarshal([]byte(body), &teams); err != nil {
+				t.Fatal(err)
+			}
+			var slugs []string
+			for _, team := range teams {
+				slugs = append(slugs, team.Slug)
+			}
+			if strings.Join(slugs, " ") != tt.expectedSlugs {
+				t.Errorf("wanted %s, got %s", tt.expectedSlugs, strings.Join(slugs, " "))
+			}
+			link := response.Header.Get("Link")
+			for _, expected := range tt.expectedLinks {
+				if !strings.Contains(link, expected) {
+					t.Errorf("wanted Link containing %s, got %s", expected, link)
+				}
+			}
+			if len(tt.expectedLinks) == 0 && link != "" {
+				t.Errorf("wanted no Link, got %s", link)
+			}
+		})
+	}
+}
+
+// TestServerLimits tests the credentials and rate limit checks
+func TestServerLimits(t *testing.T) {
+	s := newTestServer(t)
+	s.SetToken("secret")
+	s.SetRateLimit(2)
+
+	// Create your table test
+	tests := []struct {
+		name              string
+		header            http.Header
+		expectedStatus    int
+		expectedRemaining string
+	}{
+		{
+			name:           "Testing missing token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:              "Testing first request",
+			header:            http.Header{"Authorization": {"Bearer secret"}},
+			expectedStatus:    http.StatusOK,
+			expectedRemaining: "1",
+		},
+		{
+			name:              "Testing last request",
+			header:            http.Header{"Authorization": {"token secret"}},
+			expectedStatus:    http.StatusOK,
+			expectedRemaining: "0",
+		},
+		{
+			name:              "Testing exceeded rate limit",
+			header:            http.Header{"Authorization": {"Bearer secret"}},
+			expectedStatus:    http.StatusForbidden,
+			expectedRemaining: "0",
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, body := do(t, s, http.MethodGet, "/users/alice", "", tt.header)
+			if response.StatusCode != tt.expectedStatus {
+				t.Errorf("wanted %d, got %d: %s", tt.expectedStatus, response.StatusCode, body)
+			}
+			if remaining := response.Header.Get("X-RateLimit-Remaining"); remaining != tt.expectedRemaining {
+				t.Errorf("wanted %q remaining, got %q", tt.expectedRemaining, remaining)
+			}
+		})
+	}
+}
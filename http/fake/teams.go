@@ -0,0 +1,556 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Permissions a team can be granted on a repository, from lowest to highest
+var permissions = []string{"pull", "triage", "push", "maintain", "admin"}
+
+// repositoryMediaType asks the check permission endpoint for the repository
+const repositoryMediaType = "application/vnd.github.v3.repository+json"
+
+// slugify derives the slug GitHub gives a team name
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// serveOrg routes the requests under /orgs/{org}
+func (s *Server) serveOrg(req *request, o *org) {
+	switch {
+	case req.match("orgs", "*", "teams"):
+		switch req.Method {
+		case http.MethodGet:
+			s.listTeams(req, o, o.sortedTeams(func(*team) bool { return true }))
+			return
+		case http.MethodPost:
+			s.createTeam(req, o)
+			return
+		}
+	case len(req.segments) >= 4 && req.segments[2] == "teams":
+		t := o.teamBySlug(req.segments[3])
+		if t == nil {
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		s.serveTeam(req, o, t)
+		return
+	case req.match("orgs", "*", "memberships", "*"):
+		s.serveOrgMembership(req, o)
+		return
+	case req.match("orgs", "*", "members") && req.Method == http.MethodGet:
+		s.listOrgMembers(req, o)
+		return
+	case req.match("orgs", "*", "outside_collaborators") && req.Method == http.MethodGet:
+		s.listLogins(req, o.collaborators)
+		return
+	case req.match("orgs", "*", "invitations"):
+		switch req.Method {
+		case http.MethodGet:
+			s.listInvitations(req, o, func(*invitation) bool { return true })
+			return
+		case http.MethodPost:
+			s.createInvitation(req, o)
+			return
+		}
+	case req.match("orgs", "*", "invitations", "*") && req.Method == http.MethodDelete:
+		s.cancelInvitation(req, o)
+		return
+	}
+	req.error(http.StatusNotFound, "Not Found")
+}
+
+// serveTeam routes the requests under /orgs/{org}/teams/{slug}
+func (s *Server) serveTeam(req *request, o *org, t *team) {
+	switch {
+	case req.match("orgs", "*", "teams", "*"):
+		switch req.Method {
+		case http.MethodGet:
+			req.writeJSON(http.StatusOK, s.teamJSON(req, o, t, true))
+			return
+		case http.MethodPatch:
+			s.updateTeam(req, o, t)
+			return
+		case http.MethodDelete:
+			o.deleteTeam(t)
+			req.noContent()
+			return
+		}
+	case req.match("orgs", "*", "teams", "*", "teams") && req.Method == http.MethodGet:
+		s.listTeams(req, o, o.sortedTeams(func(child *team) bool { return child.parentID == t.id }))
+		return
+	case req.match("orgs", "*", "teams", "*", "members") && req.Method == http.MethodGet:
+		s.listTeamMembers(req, t)
+		return
+	case req.match("orgs", "*", "teams", "*", "memberships", "*"):
+		s.serveTeamMembership(req, o, t)
+		return
+	case req.match("orgs", "*", "teams", "*", "invitations") && req.Method == http.MethodGet:
+		s.listInvitations(req, o, func(inv *invitation) bool {
+			for _, id := range inv.teamIDs {
+				if id == t.id {
+					return true
+				}
+			}
+			return false
+		})
+		return
+	case req.match("orgs", "*", "teams", "*", "repos") && req.Method == http.MethodGet:
+		var items []interface{}
+		for _, fullName := range sortedKeys(t.repos) {
+			items = append(items, s.repoJSON(req, s.repos[fullName], t.repos[fullName]))
+		}
+		req.paginate(items)
+		return
+	case req.match("orgs", "*", "teams", "*", "repos", "*", "*"):
+		s.serveTeamRepo(req, t)
+		return
+	}
+	req.error(http.StatusNotFound, "Not Found")
+}
+
+// teamBySlug returns the team with the slug, nil when there is none
+func (o *org) teamBySlug(slug string) *team {
+	for _, t := range o.teams {
+		if t.slug == strings.ToLower(slug) {
+			return t
+		}
+	}
+	return nil
+}
+
+// sortedTeams returns the teams kept by keep ordered by name
+func (o *org) sortedTeams(keep func(*team) bool) []*team {
+	var teams []*team
+	for _, t := range o.teams {
+		if keep(t) {
+			teams = append(teams, t)
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].slug < teams[j].slug })
+	return teams
+}
+
+// isDescendant tells whether the team with id is t or nested under it
+func (o *org) isDescendant(id int, t *team) bool {
+	for id != 0 {
+		if id == t.id {
+			return true
+		}
+		parent, ok := o.teams[id]
+		if !ok {
+			return false
+		}
+		id = parent.parentID
+	}
+	return false
+}
+
+// deleteTeam deletes t with its child teams, like GitHub does, and drops them from the invitations
+func (o *org) deleteTeam(t *team) {
+	for _, child := range o.sortedTeams(func(child *team) bool { return child.parentID == t.id }) {
+		o.deleteTeam(child)
+	}
+	delete(o.teams, t.id)
+	for _, inv := range o.invitations {
+		var teamIDs []int
+		for _, id := range inv.teamIDs {
+			if id != t.id {
+				teamIDs = append(teamIDs, id)
+			}
+		}
+		inv.teamIDs = teamIDs
+	}
+}
+
+// listTeams answers with a page of teams without their counts, like the GitHub listings
+func (s *Server) listTeams(req *request, o *org, teams []*team) {
+	items := []interface{}{}
+	for _, t := range teams {
+		items = append(items, s.teamJSON(req, o, t, false))
+	}
+	req.paginate(items)
+}
+
+// teamJSON renders a team, full adds the counts and organization returned for a single team
+func (s *Server) teamJSON(req *request, o *org, t *team, full bool) map[string]interface{} {
+	rendered := map[string]interface{}{
+		"id":          t.id,
+		"node_id":     fmt.Sprintf("T_%d", t.id),
+		"name":        t.name,
+		"slug":        t.slug,
+		"description": t.description,
+		"privacy":     t.privacy,
+		"permission":  "pull",
+		"url":         fmt.Sprintf("%s/organizations/%d/team/%d", req.base, o.id, t.id),
+		"html_url":    fmt.Sprintf("https://github.com/orgs/%s/teams/%s", o.login, t.slug),
+		"parent":      nil,
+	}
+	if parent, ok := o.teams[t.parentID]; ok {
+		rendered["parent"] = map[string]interface{}{
+			"id":          parent.id,
+			"node_id":     fmt.Sprintf("T_%d", parent.id),
+			"name":        parent.name,
+			"slug":        parent.slug,
+			"description": parent.description,
+			"privacy":     parent.privacy,
+			"url":         fmt.Sprintf("%s/organizations/%d/team/%d", req.base, o.id, parent.id),
+		}
+	}
+	if full {
+		rendered["members_count"] = len(t.members) - len(t.pending)
+		rendered["repos_count"] = len(t.repos)
+		rendered["organization"] = map[string]interface{}{
+			"login":     o.login,
+			"id":        o.id,
+			"node_id":   fmt.Sprintf("O_%d", o.id),
+			"url":       fmt.Sprintf("%s/orgs/%s", req.base, o.login),
+			"repos_url": fmt.Sprintf("%s/orgs/%s/repos", req.base, o.login),
+			"type":      "Organization",
+		}
+	}
+	return rendered
+}
+
+// teamPayload is the body of the team creation
+type teamPayload struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Maintainers  []string `json:"maintainers"`
+	RepoNames    []string `json:"repo_names"`
+	Privacy      string   `json:"privacy"`
+	ParentTeamID int      `json:"parent_team_id"`
+}
+
+// createTeam serves POST /orgs/{org}/teams
+func (s *Server) createTeam(req *request, o *org) {
+	payload := teamPayload{}
+	if !req.decode(&payload) {
+		return
+	}
+	if payload.Name == "" {
+		req.validationFailed("Team", "name", "missing_field", "")
+		return
+	}
+	slug := slugify(payload.Name)
+	if o.teamBySlug(slug) != nil {
+		req.validationFailed("Team", "name", "already_exists", "Name must be unique for this org, a team named "+payload.Name+" already exists")
+		return
+	}
+	if payload.ParentTeamID != 0 {
+		if _, ok := o.teams[payload.ParentTeamID]; !ok {
+			req.validationFailed("Team", "parent_team_id", "invalid", "Parent team must exist in the organization")
+			return
+		}
+	}
+	privacy, ok := validPrivacy(req, payload.Privacy, payload.ParentTeamID)
+	if !ok {
+		return
+	}
+	for _, fullName := range payload.RepoNames {
+		if _, ok := s.repos[strings.ToLower(fullName)]; !ok {
+			req.validationFailed("Team", "repo_names", "invalid", "Repository "+fullName+" does not exist")
+			return
+		}
+	}
+	for _, login := range payload.Maintainers {
+		if _, ok := o.members[strings.ToLower(login)]; !ok {
+			req.validationFailed("Team", "maintainers", "invalid", login+" is not a member of the organization")
+			return
+		}
+	}
+
+	t := &team{
+		id:          s.newID(),
+		name:        payload.Name,
+		slug:        slug,
+		description: payload.Description,
+		privacy:     privacy,
+		parentID:    payload.ParentTeamID,
+		members:     map[string]string{},
+		pending:     map[string]bool{},
+		repos:       map[string]string{},
+	}
+	for _, login := range payload.Maintainers {
+		t.members[strings.ToLower(login)] = "maintainer"
+	}
+	for _, fullName := range payload.RepoNames {
+		t.repos[strings.ToLower(fullName)] = "pull"
+	}
+	o.teams[t.id] = t
+	req.writeJSON(http.StatusCreated, s.teamJSON(req, o, t, true))
+}
+
+// validPrivacy checks the privacy of a team, nested teams can not be secret.
+// An empty privacy defaults to secret, or closed for nested teams
+func validPrivacy(req *request, privacy string, parentID int) (string, bool) {
+	switch privacy {
+	case "":
+		if parentID != 0 {
+			return "closed", true
+		}
+		return "secret", true
+	case "secret":
+		if parentID != 0 {
+			req.validationFailed("Team", "privacy", "invalid", "A nested team can not be secret")
+			return "", false
+		}
+		return privacy, true
+	case "closed":
+		return privacy, true
+	}
+	req.validationFailed("Team", "privacy", "invalid", "Privacy must be secret or closed")
+	return "", false
+}
+
+// updateTeam serves PATCH /orgs/{org}/teams/{slug}, a null parent_team_id makes the team a root team
+func (s *Server) updateTeam(req *request, o *org, t *team) {
+	fields := map[string]json.RawMessage{}
+	if !req.decode(&fields) {
+		return
+	}
+	var name, description, privacy string
+	parentID := t.parentID
+	for field, target := range map[string]*string{"name": &name, "description": &description, "privacy": &privacy} {
+		if raw, ok := fields[field]; ok && json.Unmarshal(raw, target) != nil {
+			req.validationFailed("Team", field, "invalid", "")
+			return
+		}
+	}
+	if raw, ok := fields["parent_team_id"]; ok {
+		parentID = 0
+		if string(raw) != "null" && json.Unmarshal(raw, &parentID) != nil {
+			req.validationFailed("Team", "parent_team_id", "invalid", "")
+			return
+		}
+		if parentID != 0 {
+			if _, ok := o.teams[parentID]; !ok {
+				req.validationFailed("Team", "parent_team_id", "invalid", "Parent team must exist in the organization")
+				return
+			}
+			if o.isDescendant(parentID, t) {
+				req.validationFailed("Team", "parent_team_id", "invalid", "A team can not be nested under itself or its child teams")
+				return
+			}
+		}
+	}
+
+	slug := t.slug
+	if name != "" && name != t.name {
+		slug = slugify(name)
+		if other := o.teamBySlug(slug); other != nil && other != t {
+			req.validationFailed("Team", "name", "already_exists", "Name must be unique for this org, a team named "+name+" already exists")
+			return
+		}
+	}
+	if privacy == "" && parentID != 0 && t.privacy == "secret" {
+		privacy = "closed"
+	}
+	if privacy != "" || parentID != t.parentID {
+		if privacy == "" {
+			privacy = t.privacy
+		}
+		var ok bool
+		if privacy, ok = validPrivacy(req, privacy, parentID); !ok {
+			return
+		}
+		t.privacy = privacy
+	}
+
+	if name != "" {
+		t.name = name
+		t.slug = slug
+	}
+	if _, ok := fields["description"]; ok {
+		t.description = description
+	}
+	t.parentID = parentID
+	req.writeJSON(http.StatusOK, s.teamJSON(req, o, t, true))
+}
+
+// listTeamMembers serves GET /orgs/{org}/teams/{slug}/members, pending members are not listed
+func (s *Server) listTeamMembers(req *request, t *team) {
+	role := req.URL.Query().Get("role")
+	if role == "" {
+		role = "all"
+	}
+	if role != "all" && role != "member" && role != "maintainer" {
+		req.validationFailed("Team", "role", "invalid", "Role must be all, member or maintainer")
+		return
+	}
+	items := []interface{}{}
+	for _, login := range sortedKeys(t.members) {
+		if !t.pending[login] && (role == "all" || t.members[login] == role) {
+			items = append(items, s.userJSON(req, s.users[login]))
+		}
+	}
+	req.paginate(items)
+}
+
+// serveTeamMembership serves /orgs/{org}/teams/{slug}/memberships/{user}
+func (s *Server) serveTeamMembership(req *request, o *org, t *team) {
+	login := strings.ToLower(req.segments[5])
+	u, ok := s.users[login]
+	if !ok {
+		req.error(http.StatusNotFound, "Not Found")
+		return
+	}
+	membershipJSON := func() map[string]interface{} {
+		state := "active"
+		if t.pending[login] {
+			state = "pending"
+		}
+		return map[string]interface{}{
+			"url":   fmt.Sprintf("%s/organizations/%d/team/%d/memberships/%s", req.base, o.id, t.id, u.login),
+			"role":  t.members[login],
+			"state": state,
+		}
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		if _, ok := t.members[login]; !ok {
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		req.writeJSON(http.StatusOK, membershipJSON())
+
+	case http.MethodPut:
+		payload := struct {
+			Role string `json:"role"`
+		}{}
+		if !req.decode(&payload) {
+			return
+		}
+		if payload.Role == "" {
+			payload.Role = "member"
+		}
+		if payload.Role != "member" && payload.Role != "maintainer" {
+			req.validationFailed("TeamMember", "role", "invalid", "Role must be member or maintainer")
+			return
+		}
+		t.members[login] = payload.Role
+		// Users outside the organization are invited and stay pending until they join
+		if _, member := o.members[login]; !member && !t.pending[login] {
+			t.pending[login] = true
+			role := "direct_member"
+			if inv := o.invitationOf(login); inv != nil {
+				role = inv.role
+			}
+			s.invite(o, u.login, role, t.id)
+		}
+		req.writeJSON(http.StatusOK, membershipJSON())
+
+	case http.MethodDelete:
+		if _, ok := t.members[login]; !ok {
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		delete(t.members, login)
+		delete(t.pending, login)
+		req.noContent()
+
+	default:
+		req.error(http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveTeamRepo serves /orgs/{org}/teams/{slug}/repos/{owner}/{repo}
+func (s *Server) serveTeamRepo(req *request, t *team) {
+	fullName := strings.ToLower(req.segments[5] + "/" + req.segments[6])
+	r, ok := s.repos[fullName]
+	if !ok {
+		req.error(http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		permission, ok := t.repos[fullName]
+		if !ok {
+			req.error(http.StatusNotFound, "Not Found")
+			return
+		}
+		if req.Header.Get("Accept") != repositoryMediaType {
+			req.noContent()
+			return
+		}
+		req.writeJSON(http.StatusOK, s.repoJSON(req, r, permission))
+
+	case http.MethodPut:
+		payload := struct {
+			Permission string `json:"permission"`
+		}{}
+		if !req.decode(&payload) {
+			return
+		}
+		if payload.Permission == "" {
+			payload.Permission = "pull"
+		}
+		if permissionLevel(payload.Permission) < 0 {
+			req.validationFailed("Team", "permission", "invalid", "Permission must be pull, triage, push, maintain or admin")
+			return
+		}
+		t.repos[fullName] = payload.Permission
+		req.noContent()
+
+	case http.MethodDelete:
+		delete(t.repos, fullName)
+		req.noContent()
+
+	default:
+		req.error(http.StatusNotFound, "Not Found")
+	}
+}
+
+// permissionLevel returns the rank of the permission, -1 for unknown ones
+func permissionLevel(permission string) int {
+	for i, p := range permissions {
+		if p == permission {
+			return i
+		}
+	}
+	return -1
+}
+
+// repoJSON renders a repository with the permissions of a team on it
+func (s *Server) repoJSON(req *request, r *repo, permission string) map[string]interface{} {
+	level := permissionLevel(permission)
+	granted := map[string]bool{}
+	for i, p := range permissions {
+		granted[p] = i <= level
+	}
+	owner := s.users[strings.ToLower(r.owner)]
+	if owner == nil {
+		owner = &user{login: r.owner}
+	}
+	return map[string]interface{}{
+		"id":          r.id,
+		"node_id":     fmt.Sprintf("R_%d", r.id),
+		"name":        r.name,
+		"full_name":   r.owner + "/" + r.name,
+		"private":     r.private,
+		"url":         fmt.Sprintf("%s/repos/%s/%s", req.base, r.owner, r.name),
+		"html_url":    fmt.Sprintf("https://github.com/%s/%s", r.owner, r.name),
+		"owner":       s.userJSON(req, owner),
+		"permissions": granted,
+		"role_name":   permission,
+	}
+}
@@ -1,15 +1,21 @@
 package mock
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	httpclient "github.com/HybriStratus/test-github-groups/http"
 )
 
 // NewMockClient is a wrapper for http.Client
 func NewMockClient() httpclient.Client {
-	return Client{}
+	return Client{state: &state{}}
 }
 
 // HTTPClient is an interface that wraps the Do function from http
@@ -17,13 +23,124 @@ type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-// Client is a struct that holds a list of Responses
+// Client is a struct that holds a list of Responses. Besides the Responses matched on
+// method and URL it answers the requests set with Expect and records every call.
+// Copies of the Client share the expectations and calls, so set them up before handing
+// the Client over. The Client is safe for concurrent use, Clients built as literals as well
 type Client struct {
 	Responses map[string]map[string][]http.Response
+
+	state *state
+}
+
+// state holds the expectations and calls shared by the copies of a Client
+type state struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	// matched lists the index of the expectation matched by each call, or routed and
+	// unanswered for the calls matching none
+	matched  []int
+	anyOrder bool
+
+	// errors are the injected failures of each route, served before its Responses
+	errors    map[string]map[string][]error
+	fallbacks map[string]map[string]ResponseFunc
+	fallback  ResponseFunc
+	latency   time.Duration
+
+	inFlight    int
+	maxInFlight int
+}
+
+// statelessMu guards the Responses of the Clients without state, built as literals
+// and never set up through their methods
+var statelessMu sync.Mutex
+
+// newState returns the state of c, creating it on first use
+func (c *Client) newState() *state {
+	if c.state == nil {
+		c.state = &state{}
+	}
+	return c.state
+}
+
+// Calls matching no expectation are routed to the Responses, a fallback or an injected
+// error, or left unanswered
+const (
+	routed     = -1
+	unanswered = -2
+)
+
+// Call is a request received by the Client
+type Call struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON unmarshals the body of the call into v
+func (call Call) JSON(v interface{}) error {
+	return json.Unmarshal(call.Body, v)
+}
+
+// ResponseFunc builds the response to a request, the body of req has already been read and is given as body
+type ResponseFunc func(req *http.Request, body []byte) (*http.Response, error)
+
+// Expectation is a request the Client expects, with the response it answers with
+type Expectation struct {
+	method   string
+	url      string
+	matchers []Matcher
+	respond  ResponseFunc
+	times    int
+	calls    int
+}
+
+// Respond answers the expected request with response, its body is replayed for every call
+func (e *Expectation) Respond(response http.Response) *Expectation {
+	return e.RespondFunc(replay(response))
+}
+
+// replay answers every request with a copy of response, each with the whole body
+func replay(response http.Response) ResponseFunc {
+	var body []byte
+	if response.Body != nil {
+		body, _ = ioutil.ReadAll(response.Body)
+		response.Body.Close()
+	}
+	return func(req *http.Request, _ []byte) (*http.Response, error) {
+		copied := response
+		copied.Header = response.Header.Clone()
+		copied.Body = ioutil.NopCloser(bytes.NewReader(body))
+		copied.Request = req
+		return &copied, nil
+	}
+}
+
+// RespondFunc answers the expected request with the response built by fn
+func (e *Expectation) RespondFunc(fn ResponseFunc) *Expectation {
+	e.respond = fn
+	return e
+}
+
+// Times expects the request n times instead of once
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// String describes the expected request
+func (e *Expectation) String() string {
+	return e.method + " " + e.url
 }
 
 // SetResponses is a method that add to the list of responses held in Client
 func (c *Client) SetResponses(method string, url string, response http.Response) {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
 	if len(c.Responses) == 0 {
 		c.Responses = make(map[string]map[string][]http.Response)
 	}
@@ -41,12 +158,271 @@ func (c *Client) SetResponses(method string, url string, response http.Response)
 	c.Responses[url][method] = append(c.Responses[url][method], response)
 }
 
+// Expect expects a request with method and url matching all the matchers, answered with
+// 200 and no body until Respond or RespondFunc is called. A url without query matches the
+// request whatever its query, which is then checked with Query matchers. Expectations are
+// tried before the Responses, in the order they were set
+func (c *Client) Expect(method, url string, matchers ...Matcher) *Expectation {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	e := &Expectation{method: method, url: url, matchers: matchers, times: 1}
+	e.Respond(http.Response{StatusCode: http.StatusOK})
+	state.expectations = append(state.expectations, e)
+	return e
+}
+
+// AnyOrder lets the expected requests arrive in any order, as sent by concurrent callers
+func (c *Client) AnyOrder() {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.anyOrder = true
+}
+
+// SetFallback answers the requests with method and url with response once the Responses
+// of the route are used up, its body is replayed for every request
+func (c *Client) SetFallback(method, url string, response http.Response) {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.fallbacks == nil {
+		state.fallbacks = map[string]map[string]ResponseFunc{}
+	}
+	if state.fallbacks[url] == nil {
+		state.fallbacks[url] = map[string]ResponseFunc{}
+	}
+	state.fallbacks[url][method] = replay(response)
+}
+
+// SetDefault answers the requests nothing else answers with response instead of failing
+// them, its body is replayed for every request
+func (c *Client) SetDefault(response http.Response) {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.fallback = replay(response)
+}
+
+// InjectError fails the next request with method and url with err, before the Responses of
+// the route are served. Injecting several errors fails as many requests in order
+func (c *Client) InjectError(method, url string, err error) {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.errors == nil {
+		state.errors = map[string]map[string][]error{}
+	}
+	if state.errors[url] == nil {
+		state.errors[url] = map[string][]error{}
+	}
+	state.errors[url][method] = append(state.errors[url][method], err)
+}
+
+// SetLatency delays every response by d, requests whose context is done while waiting
+// fail with the error of the context
+func (c *Client) SetLatency(d time.Duration) {
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.latency = d
+}
+
+// Calls returns the requests received by the Client, in order
+func (c *Client) Calls() []Call {
+	if c.state == nil {
+		return nil
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	return append([]Call{}, c.state.calls...)
+}
+
+// MaxInFlight returns the highest number of requests the Client was handling at once
+func (c *Client) MaxInFlight() int {
+	if c.state == nil {
+		return 0
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	return c.state.maxInFlight
+}
+
 // Do overrides the http Do method for the mock client to use it
 func (c Client) Do(req *http.Request) (*http.Response, error) {
-	if responses, ok := c.Responses[req.URL.String()][req.Method]; ok {
+	// Behave like the http client and refuse requests whose context is done
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	if c.state == nil {
+		statelessMu.Lock()
+		defer statelessMu.Unlock()
+		if responses, ok := c.Responses[req.URL.String()][req.Method]; ok && len(responses) > 0 {
+			response := responses[0]
+			c.Responses[req.URL.String()][req.Method] = responses[1:]
+			return &response, nil
+		}
+		return nil, fmt.Errorf("no response set for %s %s", req.Method, req.URL)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	state := c.state
+	state.mu.Lock()
+	state.inFlight++
+	if state.inFlight > state.maxInFlight {
+		state.maxInFlight = state.inFlight
+	}
+	latency := state.latency
+	state.mu.Unlock()
+	defer func() {
+		state.mu.Lock()
+		state.inFlight--
+		state.mu.Unlock()
+	}()
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	state.mu.Lock()
+	respond, err := c.route(req, body)
+	state.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return respond(req, body)
+}
+
+// route records the call and picks what answers it, the state lock must be held
+func (c Client) route(req *http.Request, body []byte) (ResponseFunc, error) {
+	c.state.calls = append(c.state.calls, Call{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone(), Body: body})
+
+	if errs := c.state.errors[req.URL.String()][req.Method]; len(errs) > 0 {
+		c.state.errors[req.URL.String()][req.Method] = errs[1:]
+		c.state.matched = append(c.state.matched, routed)
+		return nil, errs[0]
+	}
+
+	var mismatches []string
+	for i, e := range c.state.expectations {
+		if e.calls >= e.times || e.method != req.Method || !e.matchesURL(req) {
+			continue
+		}
+		if err := e.match(req, body); err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %s", e, err))
+			continue
+		}
+		e.calls++
+		c.state.matched = append(c.state.matched, i)
+		return e.respond, nil
+	}
+
+	if responses, ok := c.Responses[req.URL.String()][req.Method]; ok && len(responses) > 0 {
 		response := responses[0]
 		c.Responses[req.URL.String()][req.Method] = responses[1:]
-		return &response, nil
+		c.state.matched = append(c.state.matched, routed)
+		return func(*http.Request, []byte) (*http.Response, error) { return &response, nil }, nil
+	}
+	if fallback, ok := c.state.fallbacks[req.URL.String()][req.Method]; ok {
+		c.state.matched = append(c.state.matched, routed)
+		return fallback, nil
+	}
+	if c.state.fallback != nil {
+		c.state.matched = append(c.state.matched, routed)
+		return c.state.fallback, nil
+	}
+	c.state.matched = append(c.state.matched, unanswered)
+	if len(mismatches) > 0 {
+		return nil, fmt.Errorf("no response set for %s %s, %s", req.Method, req.URL, strings.Join(mismatches, ", "))
+	}
+	return nil, fmt.Errorf("no response set for %s %s", req.Method, req.URL)
+}
+
+// matchesURL tells whether the request is sent to the expected URL
+func (e *Expectation) matchesURL(req *http.Request) bool {
+	if strings.Contains(e.url, "?") {
+		return e.url == req.URL.String()
+	}
+	withoutQuery := *req.URL
+	withoutQuery.RawQuery = ""
+	return e.url == withoutQuery.String()
+}
+
+// match returns the mismatch of the first matcher the request fails
+func (e *Expectation) match(req *http.Request, body []byte) error {
+	for _, matcher := range e.matchers {
+		if err := matcher(req, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TB is the part of testing.TB the assertions report through
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertExpectations fails t unless every expected request was made as many times as
+// expected, in the order the expectations were set unless AnyOrder was called, no
+// request went unanswered and no response set with SetResponses nor injected error is left over
+func (c *Client) AssertExpectations(t TB) bool {
+	t.Helper()
+	ok := true
+	state := c.newState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	for _, e := range state.expectations {
+		if e.calls != e.times {
+			t.Errorf("expected %s %d times, got %d calls", e, e.times, e.calls)
+			ok = false
+		}
+	}
+	last := -1
+	for i, index := range state.matched {
+		call := state.calls[i]
+		switch {
+		case index == unanswered:
+			t.Errorf("unexpected call %s %s", call.Method, call.URL)
+			ok = false
+		case index >= 0 && index < last && !state.anyOrder:
+			t.Errorf("call %s %s made out of order, expected after %s", call.Method, call.URL, state.expectations[last])
+			ok = false
+		case index >= 0:
+			last = index
+		}
+	}
+	for url, methods := range c.Responses {
+		for method, responses := range methods {
+			if len(responses) > 0 {
+				t.Errorf("%d responses left over for %s %s", len(responses), method, url)
+				ok = false
+			}
+		}
+	}
+	for url, methods := range state.errors {
+		for method, errs := range methods {
+			if len(errs) > 0 {
+				t.Errorf("%d injected errors left over for %s %s", len(errs), method, url)
+				ok = false
+			}
+		}
 	}
-	return nil, fmt.Errorf("no response set")
+	return ok
 }
@@ -1,10 +1,20 @@
 package mock
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 )
 
 // Test used to test the Mock Client
@@ -70,3 +80,438 @@ func TestClientMock_Do(t *testing.T) {
 		})
 	}
 }
+
+// Test used to test that the Mock Client honors cancelled requests
+func TestClientMock_DoCancelled(t *testing.T) {
+
+	testURL, _ := url.Parse("https://api.github.com/orgs/HybriStratus/teams")
+	client := Client{}
+	client.SetResponses("GET", testURL.String(), http.Response{StatusCode: 200})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := (&http.Request{Method: "GET", URL: testURL}).WithContext(ctx)
+	if _, err := client.Do(req); err != context.Canceled {
+		t.Errorf("wanted %v, got %v", context.Canceled, err)
+	}
+
+	// The cancelled request must not consume the response
+	if got, _ := client.Do(&http.Request{Method: "GET", URL: testURL}); got == nil || got.StatusCode != 200 {
+		t.Errorf("wanted status 200, got %v", got)
+	}
+}
+
+// Test used to test the expectations of the Mock Client and their matchers
+func TestClientMock_Expect(t *testing.T) {
+
+	teamsURL := "https://api.github.com/orgs/HybriStratus/teams"
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		matchers       []Matcher
+		request        func() *http.Request
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:     "Testing JSON body with other key order and spacing",
+			matchers: []Matcher{JSONBody(`{"name": "team", "privacy": "closed"}`)},
+			request: func() *http.Request {
+				req, _ := http.NewRequest("POST", teamsURL, strings.NewReader(`{"privacy":"closed","name":"team"}`))
+				return req
+			},
+			expectedStatus: 201,
+		},
+		{
+			name:     "Testing JSON body from a value",
+			matchers: []Matcher{JSONBody(map[string]interface{}{"name": "team"})},
+			request: func() *http.Request {
+				req, _ := http.NewRequest("POST", teamsURL, strings.NewReader(`{"name":"team"}`))
+				return req
+			},
+			expectedStatus: 201,
+		},
+		{
+			name:     "Testing JSON body mismatch",
+			matchers: []Matcher{JSONBody(`{"name": "team"}`)},
+			request: func() *http.Request {
+				req, _ := http.NewRequest("POST", teamsURL, strings.NewReader(`{"name":"other"}`))
+				return req
+			},
+			expectedError: `no response set for POST ` + teamsURL + `, POST ` + teamsURL + `: body is {"name":"other"}`,
+		},
+		{
+			name:     "Testing header and query",
+			matchers: []Matcher{Header("Accept", "application/vnd.github.v3+json"), Query("per_page", "100")},
+			request: func() *http.Request {
+				req, _ := http.NewRequest("POST", teamsURL+"?per_page=100", nil)
+				req.Header.Set("Accept", "application/vnd.github.v3+json")
+				return req
+			},
+			expectedStatus: 201,
+		},
+		{
+			name:     "Testing header mismatch",
+			matchers: []Matcher{Header("Accept", "application/vnd.github.v3+json")},
+			request: func() *http.Request {
+				req, _ := http.NewRequest("POST", teamsURL, nil)
+				return req
+			},
+			expectedError: `no response set for POST ` + teamsURL + `, POST ` + teamsURL + `: header Accept is "", wanted "application/vnd.github.v3+json"`,
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			client := Client{}
+			client.Expect("POST", teamsURL, tt.matchers...).Respond(http.Response{StatusCode: 201})
+
+			got, err := client.Do(tt.request())
+			if err != nil || tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("wanted error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if got.StatusCode != tt.expectedStatus {
+				t.Errorf("wanted %d, got %d", tt.expectedStatus, got.StatusCode)
+			}
+		})
+	}
+}
+
+// Test used to test that the Mock Client replays bodies and builds responses with functions
+func TestClientMock_Respond(t *testing.T) {
+
+	teamURL := "https://api.github.com/orgs/HybriStratus/teams/team"
+	client := Client{}
+	client.Expect("GET", teamURL).Respond(http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"id": 1}`)),
+	}).Times(2)
+	client.Expect("PATCH", teamURL).RespondFunc(func(req *http.Request, body []byte) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Do(&http.Request{Method: "GET", URL: mustParse(teamURL)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body, _ := ioutil.ReadAll(response.Body); string(body) != `{"id": 1}` {
+			t.Errorf("wanted the body replayed, got %q", body)
+		}
+	}
+
+	req, _ := http.NewRequest("PATCH", teamURL, strings.NewReader(`{"name":"renamed"}`))
+	response, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := ioutil.ReadAll(response.Body); string(body) != `{"name":"renamed"}` {
+		t.Errorf("wanted the request body echoed, got %q", body)
+	}
+
+	calls := client.Calls()
+	if len(calls) != 3 || calls[2].Method != "PATCH" {
+		t.Fatalf("wanted 3 calls ending with PATCH, got %v", calls)
+	}
+	payload := map[string]string{}
+	if err := calls[2].JSON(&payload); err != nil || payload["name"] != "renamed" {
+		t.Errorf("wanted the recorded body, got %v %v", payload, err)
+	}
+	if !client.AssertExpectations(t) {
+		t.Errorf("wanted the expectations met")
+	}
+}
+
+// recorder is a TB recording the failures reported to it
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// Test used to test the failures reported by AssertExpectations
+func TestClientMock_AssertExpectations(t *testing.T) {
+
+	teamsURL := "https://api.github.com/orgs/HybriStratus/teams"
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		anyOrder bool
+		leftOver bool
+		methods  []string
+		expected []string
+	}{
+		{
+			name:    "Testing expectations met in order",
+			methods: []string{"POST", "GET"},
+		},
+		{
+			name:     "Testing missing call",
+			methods:  []string{"POST"},
+			expected: []string{"expected GET " + teamsURL + " 1 times, got 0 calls"},
+		},
+		{
+			name:     "Testing calls out of order",
+			methods:  []string{"GET", "POST"},
+			expected: []string{"call POST " + teamsURL + " made out of order, expected after GET " + teamsURL},
+		},
+		{
+			name:     "Testing calls in any order",
+			anyOrder: true,
+			methods:  []string{"GET", "POST"},
+		},
+		{
+			name:     "Testing unexpected call and left over response",
+			leftOver: true,
+			methods:  []string{"POST", "GET", "DELETE"},
+			expected: []string{
+				"unexpected call DELETE " + teamsURL,
+				"1 responses left over for PUT " + teamsURL,
+			},
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			client := Client{}
+			client.Expect("POST", teamsURL)
+			client.Expect("GET", teamsURL)
+			if tt.anyOrder {
+				client.AnyOrder()
+			}
+			if tt.leftOver {
+				client.SetResponses("PUT", teamsURL, http.Response{StatusCode: 200})
+			}
+			for _, method := range tt.methods {
+				client.Do(&http.Request{Method: method, URL: mustParse(teamsURL)})
+			}
+
+			r := &recorder{}
+			ok := client.AssertExpectations(r)
+			if ok != (len(tt.expected) == 0) || !reflect.DeepEqual(r.errors, tt.expected) {
+				t.Errorf("wanted %q, got %q", tt.expected, r.errors)
+			}
+		})
+	}
+}
+
+// mustParse parses a URL of the tests
+func mustParse(rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// Test used to test that concurrent requests get every queued response exactly once
+func TestClientMock_Concurrent(t *testing.T) {
+
+	const requests = 50
+	memberURL := "https://api.github.com/orgs/HybriStratus/teams/team/memberships/"
+	client := Client{}
+	for i := 0; i < requests; i++ {
+		client.SetResponses("PUT", memberURL+"alice", http.Response{StatusCode: 200 + i})
+		client.SetResponses("PUT", fmt.Sprintf("%suser%d", memberURL, i), http.Response{StatusCode: 200})
+	}
+	client.SetLatency(time.Millisecond)
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, user := range []string{"alice", fmt.Sprintf("user%d", i)} {
+				response, err := client.Do(&http.Request{Method: "PUT", URL: mustParse(memberURL + user)})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if user == "alice" {
+					statuses <- response.StatusCode
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(statuses)
+
+	seen := map[int]bool{}
+	for status := range statuses {
+		seen[status] = true
+	}
+	if len(seen) != requests {
+		t.Errorf("wanted %d distinct responses, got %d", requests, len(seen))
+	}
+	if len(client.Calls()) != 2*requests {
+		t.Errorf("wanted %d calls recorded, got %d", 2*requests, len(client.Calls()))
+	}
+	if client.MaxInFlight() < 2 {
+		t.Errorf("wanted concurrent requests, got %d in flight at most", client.MaxInFlight())
+	}
+	client.AssertExpectations(t)
+}
+
+// Test used to test concurrent requests to Clients never set up through their methods
+func TestClientMock_ConcurrentLiteral(t *testing.T) {
+
+	const requests = 50
+	teamURL := "https://api.github.com/orgs/HybriStratus/teams/team"
+	responses := make([]http.Response, requests)
+	for i := range responses {
+		responses[i] = http.Response{StatusCode: 200 + i}
+	}
+
+	// Create your table test
+	tests := []struct {
+		name   string
+		client Client
+	}{
+		{
+			name:   "Testing literal client",
+			client: Client{Responses: map[string]map[string][]http.Response{teamURL: {"GET": responses}}},
+		},
+		{
+			name:   "Testing client of NewMockClient",
+			client: NewMockClient().(Client),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.client.Responses == nil {
+				tt.client.Responses = map[string]map[string][]http.Response{teamURL: {"GET": append([]http.Response{}, responses...)}}
+			}
+
+			var wg sync.WaitGroup
+			statuses := make(chan int, requests)
+			for i := 0; i < requests; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					response, err := tt.client.Do(&http.Request{Method: "GET", URL: mustParse(teamURL)})
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					statuses <- response.StatusCode
+				}()
+			}
+			wg.Wait()
+			close(statuses)
+
+			seen := map[int]bool{}
+			for status := range statuses {
+				seen[status] = true
+			}
+			if len(seen) != requests {
+				t.Errorf("wanted %d distinct responses, got %d", requests, len(seen))
+			}
+		})
+	}
+}
+
+// Test used to test fallback responses, latency and injected errors
+func TestClientMock_Faults(t *testing.T) {
+
+	teamURL := "https://api.github.com/orgs/HybriStratus/teams/team"
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		setup    func(client *Client)
+		timeout  time.Duration
+		expected []string
+	}{
+		{
+			name: "Testing fallback after the queued responses",
+			setup: func(client *Client) {
+				client.SetResponses("GET", teamURL, http.Response{StatusCode: 200})
+				client.SetFallback("GET", teamURL, http.Response{StatusCode: 404, Body: ioutil.NopCloser(strings.NewReader("gone"))})
+			},
+			expected: []string{"200 ", "404 gone", "404 gone"},
+		},
+		{
+			name: "Testing default response",
+			setup: func(client *Client) {
+				client.SetDefault(http.Response{StatusCode: 503})
+			},
+			expected: []string{"503 ", "503 ", "503 "},
+		},
+		{
+			name: "Testing injected errors before the responses",
+			setup: func(client *Client) {
+				client.SetResponses("GET", teamURL, http.Response{StatusCode: 200})
+				client.InjectError("GET", teamURL, ErrTimeout)
+				client.InjectError("GET", teamURL, ErrConnectionReset)
+			},
+			expected: []string{"timeout", "connection reset", "200 "},
+		},
+		{
+			name: "Testing latency past the deadline",
+			setup: func(client *Client) {
+				client.SetDefault(http.Response{StatusCode: 200})
+				client.SetLatency(time.Second)
+			},
+			timeout:  10 * time.Millisecond,
+			expected: []string{"deadline exceeded", "deadline exceeded", "deadline exceeded"},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			client := Client{}
+			tt.setup(&client)
+
+			var got []string
+			for range tt.expected {
+				ctx := context.Background()
+				if tt.timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+					defer cancel()
+				}
+				req := (&http.Request{Method: "GET", URL: mustParse(teamURL)}).WithContext(ctx)
+				response, err := client.Do(req)
+				var netErr net.Error
+				switch {
+				case errors.Is(err, context.DeadlineExceeded):
+					got = append(got, "deadline exceeded")
+				case errors.As(err, &netErr) && netErr.Timeout():
+					got = append(got, "timeout")
+				case errors.Is(err, syscall.ECONNRESET):
+					got = append(got, "connection reset")
+				case err != nil:
+					got = append(got, err.Error())
+				default:
+					body := []byte{}
+					if response.Body != nil {
+						body, _ = ioutil.ReadAll(response.Body)
+					}
+					got = append(got, fmt.Sprintf("%d %s", response.StatusCode, body))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("wanted %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
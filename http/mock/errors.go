@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// Network failures to inject with InjectError, they are reported like the ones of the http client
+var (
+	// ErrTimeout is a request that timed out, it is a net.Error whose Timeout is true
+	ErrTimeout error = &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+	// ErrConnectionReset is a connection reset by GitHub, it matches syscall.ECONNRESET with errors.Is
+	ErrConnectionReset error = &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+)
+
+// timeoutError is the error of a timed out network operation
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
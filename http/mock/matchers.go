@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// Matcher checks a request expected by the Client, it returns the mismatch when the
+// request does not match. The body of the request is given as body
+type Matcher func(req *http.Request, body []byte) error
+
+// JSONBody matches requests whose body is the same JSON document as expected, whatever
+// the order of the keys and the spacing. A string or []byte expected is JSON text,
+// anything else is marshalled
+func JSONBody(expected interface{}) Matcher {
+	var want interface{}
+	err := unmarshalExpected(expected, &want)
+	return func(req *http.Request, body []byte) error {
+		if err != nil {
+			return fmt.Errorf("expected body is not JSON: %w", err)
+		}
+		var got interface{}
+		if err := json.Unmarshal(body, &got); err != nil {
+			return fmt.Errorf("body %q is not JSON", body)
+		}
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("body is %s", body)
+		}
+		return nil
+	}
+}
+
+// unmarshalExpected reads the JSON document of expected into v
+func unmarshalExpected(expected interface{}, v interface{}) error {
+	var document []byte
+	switch expected := expected.(type) {
+	case string:
+		document = []byte(expected)
+	case []byte:
+		document = expected
+	default:
+		var err error
+		document, err = json.Marshal(expected)
+		if err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(document, v)
+}
+
+// Header matches requests whose header key is value
+func Header(key, value string) Matcher {
+	return func(req *http.Request, _ []byte) error {
+		if got := req.Header.Get(key); got != value {
+			return fmt.Errorf("header %s is %q, wanted %q", key, got, value)
+		}
+		return nil
+	}
+}
+
+// Query matches requests whose query parameter key is value
+func Query(key, value string) Matcher {
+	return func(req *http.Request, _ []byte) error {
+		if got := req.URL.Query().Get(key); got != value {
+			return fmt.Errorf("query parameter %s is %q, wanted %q", key, got, value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+)
+
+// Change is a mutating request that was recorded instead of being sent
+type Change struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Client is a http.Client that sends read-only requests and records the mutating
+// POST, PATCH, PUT and DELETE requests in a plan instead of sending them
+type Client struct {
+	client httpclient.Client
+
+	mu      sync.Mutex
+	changes []Change
+}
+
+// NewClient wraps client in plan mode
+func NewClient(client httpclient.Client) *Client {
+	return &Client{client: client}
+}
+
+// Do sends GET and HEAD requests, every other request is recorded and answered
+// with the success status GitHub would return, echoing the request body
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return c.client.Do(req)
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	change := Change{Method: req.Method, URL: req.URL.String()}
+	if len(body) > 0 {
+		var compact bytes.Buffer
+		if json.Compact(&compact, body) == nil {
+			change.Body = compact.Bytes()
+		} else {
+			// Keep bodies that are not JSON readable in the plan
+			change.Body, _ = json.Marshal(string(body))
+		}
+	}
+	c.mu.Lock()
+	c.changes = append(c.changes, change)
+	c.mu.Unlock()
+
+	statusCode := http.StatusOK
+	switch req.Method {
+	case http.MethodPost:
+		statusCode = http.StatusCreated
+	case http.MethodDelete:
+		statusCode = http.StatusNoContent
+	}
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Changes returns the recorded changes in the order they were planned
+func (c *Client) Changes() []Change {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Change(nil), c.changes...)
+}
+
+// WriteText writes the plan in a human-readable form
+func (c *Client) WriteText(w io.Writer) error {
+	changes := c.Changes()
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes planned")
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "Planned changes (%d):\n", len(changes))
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		_, err = fmt.Fprintf(w, "  %-6s %s\n", change.Method, change.URL)
+		if err != nil {
+			return err
+		}
+		if len(change.Body) > 0 {
+			_, err = fmt.Fprintf(w, "         %s\n", change.Body)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the plan as a JSON document
+func (c *Client) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Changes []Change `json:"changes"`
+	}{Changes: c.Changes()})
+}
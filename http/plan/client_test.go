@@ -0,0 +1,106 @@
+package plan
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// Test used to test that the plan Client sends reads and records writes
+func TestClient_Do(t *testing.T) {
+
+	teamURL := "https://api.github.com/orgs/HybriStratus/teams/test_team"
+
+	// Create your table test
+	var tests = []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedChange *Change
+	}{
+		{
+			name:           "GET is sent",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "POST is planned",
+			method:         http.MethodPost,
+			body:           `{"name": "test_team"}`,
+			expectedStatus: http.StatusCreated,
+			expectedChange: &Change{Method: http.MethodPost, URL: teamURL, Body: []byte(`{"name":"test_team"}`)},
+		},
+		{
+			name:           "PATCH is planned",
+			method:         http.MethodPatch,
+			body:           `{"privacy": "closed"}`,
+			expectedStatus: http.StatusOK,
+			expectedChange: &Change{Method: http.MethodPatch, URL: teamURL, Body: []byte(`{"privacy":"closed"}`)},
+		},
+		{
+			name:           "DELETE is planned",
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusNoContent,
+			expectedChange: &Change{Method: http.MethodDelete, URL: teamURL},
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Only reads are answered by the mock Client
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodGet, teamURL, http.Response{StatusCode: http.StatusOK})
+			client := NewClient(mockClient)
+
+			req, _ := http.NewRequest(tt.method, teamURL, strings.NewReader(tt.body))
+			got, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("wanted no error, got %v", err)
+			}
+			if got.StatusCode != tt.expectedStatus {
+				t.Errorf("wanted %d, got %d", tt.expectedStatus, got.StatusCode)
+			}
+
+			changes := client.Changes()
+			if tt.expectedChange == nil {
+				if len(changes) != 0 {
+					t.Errorf("wanted no changes, got %v", changes)
+				}
+				return
+			}
+			if len(changes) != 1 || changes[0].Method != tt.expectedChange.Method ||
+				changes[0].URL != tt.expectedChange.URL || string(changes[0].Body) != string(tt.expectedChange.Body) {
+				t.Errorf("wanted %v, got %v", *tt.expectedChange, changes)
+			}
+		})
+	}
+}
+
+// Test used to test the rendering of the plan
+func TestClient_Write(t *testing.T) {
+
+	client := NewClient(mock.Client{})
+	req, _ := http.NewRequest(http.MethodPut, "https://api.github.com/orgs/HybriStratus/teams/test_team/memberships/test_user", strings.NewReader(`{"role":"member"}`))
+	client.Do(req)
+
+	var text bytes.Buffer
+	client.WriteText(&text)
+	expectedText := "Planned changes (1):\n" +
+		"  PUT    https://api.github.com/orgs/HybriStratus/teams/test_team/memberships/test_user\n" +
+		"         {\"role\":\"member\"}\n"
+	if text.String() != expectedText {
+		t.Errorf("wanted %q, got %q", expectedText, text.String())
+	}
+
+	var jsonPlan bytes.Buffer
+	client.WriteJSON(&jsonPlan)
+	if !strings.Contains(jsonPlan.String(), `"method": "PUT"`) || !strings.Contains(jsonPlan.String(), `"body": {`) {
+		t.Errorf("wanted the PUT change in the JSON plan, got %s", jsonPlan.String())
+	}
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+)
+
+// secondaryBackoff is how long to back off from a secondary rate limit that does not
+// come with a Retry-After header, as recommended by the GitHub documentation
+const secondaryBackoff = time.Minute
+
+// ErrRateLimited is returned when a request would have to wait longer than MaxWait
+var ErrRateLimited = errors.New("github rate limit exceeded")
+
+// Rate is the rate-limit budget reported by GitHub
+type Rate struct {
+	Limit     int
+	Remaining int
+	Used      int
+	// Reset is the time at which the primary budget is replenished
+	Reset time.Time
+	// SecondaryReset is the time until which GitHub asked callers to back off after
+	// hitting a secondary rate limit, it is zero when no such limit is active
+	SecondaryReset time.Time
+}
+
+// Client is a http.Client that tracks the GitHub rate-limit headers and delays
+// requests until the exhausted budget is reset
+type Client struct {
+	client httpclient.Client
+	// MaxWait bounds how long a request is delayed. Requests that would have to wait
+	// longer fail with ErrRateLimited, zero waits for as long as needed
+	MaxWait time.Duration
+
+	mu    sync.Mutex
+	rate  Rate
+	known bool
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient wraps client with rate-limit tracking
+func NewClient(client httpclient.Client) *Client {
+	return &Client{
+		client: client,
+		now:    time.Now,
+		sleep:  httpclient.Sleep,
+	}
+}
+
+// Do waits for the rate-limit budget, sends the request and records the budget
+// reported by the response
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	wait, reset := c.waitTime()
+	if wait > 0 {
+		if c.MaxWait > 0 && wait > c.MaxWait {
+			return nil, fmt.Errorf("%w until %s", ErrRateLimited, reset.Format(time.RFC3339))
+		}
+		if err := c.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	c.update(response)
+	return response, nil
+}
+
+// Rate returns the last rate-limit budget reported by GitHub
+func (c *Client) Rate() Rate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rate
+}
+
+// waitTime returns how long the next request has to wait and the time it waits for
+func (c *Client) waitTime() (time.Duration, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.rate.SecondaryReset.After(now) {
+		return c.rate.SecondaryReset.Sub(now), c.rate.SecondaryReset
+	}
+	if c.known && c.rate.Remaining == 0 && c.rate.Reset.After(now) {
+		return c.rate.Reset.Sub(now), c.rate.Reset
+	}
+	return 0, time.Time{}
+}
+
+// update records the rate-limit headers of the response
+func (c *Client) update(response *http.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := response.Header
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		c.known = true
+		c.rate.Remaining = remaining
+		c.rate.Limit, _ = strconv.Atoi(header.Get("X-RateLimit-Limit"))
+		c.rate.Used, _ = strconv.Atoi(header.Get("X-RateLimit-Used"))
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			c.rate.Reset = time.Unix(reset, 0)
+		}
+	}
+
+	if response.StatusCode != http.StatusForbidden && response.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		c.rate.SecondaryReset = c.now().Add(time.Duration(seconds) * time.Second)
+		return
+	}
+	if c.known && c.rate.Remaining == 0 {
+		// The primary budget is exhausted, waiting for Reset is enough
+		return
+	}
+	if response.StatusCode == http.StatusTooManyRequests || isSecondaryLimit(response) {
+		c.rate.SecondaryReset = c.now().Add(secondaryBackoff)
+	}
+}
+
+// isSecondaryLimit tells a secondary rate limit apart from a plain permission error by
+// its message, the body is restored so callers can still read it
+func isSecondaryLimit(response *http.Response) bool {
+	if response.Body == nil {
+		return false
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// Test used to test the rate-limit tracking of the Client
+func TestClient_Do(t *testing.T) {
+
+	now := time.Unix(1650000000, 0)
+	testURL, _ := url.Parse("https://api.github.com/orgs/HybriStratus/teams")
+	reset := strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)
+
+	// Create your table test
+	var tests = []struct {
+		name          string
+		first         http.Response
+		maxWait       time.Duration
+		expectedWait  time.Duration
+		expectedRate  Rate
+		expectedError error
+	}{
+		{
+			name: "Remaining budget does not wait",
+			first: http.Response{
+				StatusCode: http.StatusOK,
+				Header: http.Header{
+					"X-Ratelimit-Limit":     {"5000"},
+					"X-Ratelimit-Remaining": {"4999"},
+					"X-Ratelimit-Used":      {"1"},
+					"X-Ratelimit-Reset":     {reset},
+				},
+			},
+			expectedWait: 0,
+			expectedRate: Rate{Limit: 5000, Remaining: 4999, Used: 1, Reset: now.Add(30 * time.Second)},
+		},
+		{
+			name: "Exhausted primary budget waits until reset",
+			first: http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"X-Ratelimit-Limit":     {"5000"},
+					"X-Ratelimit-Remaining": {"0"},
+					"X-Ratelimit-Used":      {"5000"},
+					"X-Ratelimit-Reset":     {reset},
+				},
+			},
+			expectedWait: 30 * time.Second,
+			expectedRate: Rate{Limit: 5000, Remaining: 0, Used: 5000, Reset: now.Add(30 * time.Second)},
+		},
+		{
+			name: "Secondary limit waits for Retry-After",
+			first: http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"Retry-After": {"10"},
+				},
+			},
+			expectedWait: 10 * time.Second,
+			expectedRate: Rate{SecondaryReset: now.Add(10 * time.Second)},
+		},
+		{
+			name: "Secondary limit without Retry-After backs off a minute",
+			first: http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"message": "You have exceeded a secondary rate limit."}`))),
+			},
+			expectedWait: time.Minute,
+			expectedRate: Rate{SecondaryReset: now.Add(time.Minute)},
+		},
+		{
+			name: "Permission errors do not wait",
+			first: http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"message": "Must have admin rights to Repository."}`))),
+			},
+			expectedWait: 0,
+		},
+		{
+			name: "Waits longer than MaxWait fail",
+			first: http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header: http.Header{
+					"Retry-After": {"120"},
+				},
+			},
+			maxWait:       time.Minute,
+			expectedRate:  Rate{SecondaryReset: now.Add(120 * time.Second)},
+			expectedError: ErrRateLimited,
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client with the synthetic rate-limit response first
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodGet, testURL.String(), tt.first)
+			mockClient.SetResponses(http.MethodGet, testURL.String(), http.Response{StatusCode: http.StatusOK})
+
+			var waited time.Duration
+			client := NewClient(mockClient)
+			client.MaxWait = tt.maxWait
+			client.now = func() time.Time { return now }
+			client.sleep = func(ctx context.Context, d time.Duration) error {
+				waited += d
+				return nil
+			}
+
+			req := &http.Request{Method: http.MethodGet, URL: testURL, Header: http.Header{}}
+			if _, err := client.Do(req); err != nil {
+				t.Fatalf("wanted no error, got %v", err)
+			}
+			if got := client.Rate(); got != tt.expectedRate {
+				t.Errorf("wanted %v, got %v", tt.expectedRate, got)
+			}
+
+			_, err := client.Do(req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("wanted %v, got %v", tt.expectedError, err)
+			}
+			if waited != tt.expectedWait {
+				t.Errorf("wanted to wait %v, got %v", tt.expectedWait, waited)
+			}
+		})
+	}
+}
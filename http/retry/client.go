@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+)
+
+// Default retry settings used by NewClient
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 500 * time.Millisecond
+	DefaultMaxDelay    = 30 * time.Second
+)
+
+// Attempt describes a failed attempt that is about to be retried
+type Attempt struct {
+	Request *http.Request
+	// Number is the number of the failed attempt, starting at 1
+	Number int
+	// Response is the response of the failed attempt, nil on connection errors
+	Response *http.Response
+	// Err is the connection error of the failed attempt, if any
+	Err error
+	// Delay is how long the client waits before the next attempt
+	Delay time.Duration
+}
+
+// Client is a http.Client that retries transient GitHub errors with jittered
+// exponential backoff
+type Client struct {
+	client httpclient.Client
+	// MaxAttempts is the total number of attempts made for a request
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled on every further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay
+	MaxDelay time.Duration
+	// RetryNonIdempotent allows retrying POST and PATCH requests on error responses,
+	// by default they are only retried on connection errors
+	RetryNonIdempotent bool
+	// OnRetry is called before every retry
+	OnRetry func(Attempt)
+
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(n int64) int64
+}
+
+// NewClient wraps client with the default retry settings
+func NewClient(client httpclient.Client) *Client {
+	return &Client{
+		client:      client,
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+		sleep:       httpclient.Sleep,
+		jitter:      rand.Int63n,
+	}
+}
+
+// Do sends the request, retrying it while the failure is transient
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		response, err := c.client.Do(attemptReq)
+		if attempt >= c.MaxAttempts || !c.shouldRetry(req, response, err) {
+			return response, err
+		}
+
+		delay := c.backoff(attempt, response)
+		if c.OnRetry != nil {
+			c.OnRetry(Attempt{
+				Request:  req,
+				Number:   attempt,
+				Response: response,
+				Err:      err,
+				Delay:    delay,
+			})
+		}
+		if response != nil && response.Body != nil {
+			// Drain the body so the connection can be reused
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		if err := c.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry tells whether the outcome of an attempt is worth retrying
+func (c *Client) shouldRetry(req *http.Request, response *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	// The body can only be sent again when it can be recreated
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	if !idempotent(req.Method) && !c.RetryNonIdempotent {
+		return false
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden:
+		// Only rate-limited requests succeed when sent again
+		return response.Header.Get("Retry-After") != "" || response.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt, honoring Retry-After
+func (c *Client) backoff(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	delay := c.BaseDelay << uint(attempt-1)
+	if delay <= 0 || delay > c.MaxDelay {
+		delay = c.MaxDelay
+	}
+	// Wait at least half the delay and a random part of the other half
+	half := int64(delay / 2)
+	if half == 0 {
+		return delay
+	}
+	return time.Duration(half + c.jitter(half+1))
+}
+
+// idempotent tells whether a request with method can be sent twice safely
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/HybriStratus/test-github-groups/http/mock"
+)
+
+// clientFunc adapts a function to the http.Client interface
+type clientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls the function
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Test used to test the retries of the Client
+func TestClient_Do(t *testing.T) {
+
+	testURL, _ := url.Parse("https://api.github.com/orgs/HybriStratus/teams/test_team")
+
+	// Create your table test
+	var tests = []struct {
+		name               string
+		method             string
+		responses          []http.Response
+		errors             []error
+		retryNonIdempotent bool
+		expectedDelays     []time.Duration
+		expectedStatus     int
+	}{
+		{
+			name:   "Retries GET on bad gateway",
+			method: http.MethodGet,
+			responses: []http.Response{
+				{StatusCode: http.StatusBadGateway},
+				{StatusCode: http.StatusBadGateway},
+				{StatusCode: http.StatusOK},
+			},
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Gives up after MaxAttempts",
+			method: http.MethodDelete,
+			responses: []http.Response{
+				{StatusCode: http.StatusServiceUnavailable},
+				{StatusCode: http.StatusServiceUnavailable},
+				{StatusCode: http.StatusServiceUnavailable},
+			},
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "Honors Retry-After",
+			method: http.MethodPut,
+			responses: []http.Response{
+				{StatusCode: http.StatusForbidden, Header: http.Header{"Retry-After": {"7"}}},
+				{StatusCode: http.StatusOK},
+			},
+			expectedDelays: []time.Duration{7 * time.Second},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Does not retry client errors",
+			method: http.MethodGet,
+			responses: []http.Response{
+				{StatusCode: http.StatusNotFound},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Does not retry POST on bad gateway",
+			method: http.MethodPost,
+			responses: []http.Response{
+				{StatusCode: http.StatusBadGateway},
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:   "Retries POST on bad gateway when opted in",
+			method: http.MethodPost,
+			responses: []http.Response{
+				{StatusCode: http.StatusBadGateway},
+				{StatusCode: http.StatusCreated},
+			},
+			retryNonIdempotent: true,
+			expectedDelays:     []time.Duration{100 * time.Millisecond},
+			expectedStatus:     http.StatusCreated,
+		},
+		{
+			name:   "Retries timeouts and connection resets",
+			method: http.MethodGet,
+			responses: []http.Response{
+				{StatusCode: http.StatusOK},
+			},
+			errors:         []error{mock.ErrTimeout, mock.ErrConnectionReset},
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.responses {
+				mockClient.SetResponses(tt.method, testURL.String(), response)
+			}
+			for _, err := range tt.errors {
+				mockClient.InjectError(tt.method, testURL.String(), err)
+			}
+
+			var delays []time.Duration
+			client := NewClient(mockClient)
+			client.BaseDelay = 100 * time.Millisecond
+			client.RetryNonIdempotent = tt.retryNonIdempotent
+			client.OnRetry = func(attempt Attempt) { delays = append(delays, attempt.Delay) }
+			client.jitter = func(n int64) int64 { return n - 1 }
+			client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+			req, _ := http.NewRequest(tt.method, testURL.String(), bytes.NewBufferString(`{"name": "test_team"}`))
+			got, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("wanted no error, got %v", err)
+			}
+			if got.StatusCode != tt.expectedStatus {
+				t.Errorf("wanted %d, got %d", tt.expectedStatus, got.StatusCode)
+			}
+			if len(delays) != len(tt.expectedDelays) {
+				t.Fatalf("wanted delays %v, got %v", tt.expectedDelays, delays)
+			}
+			for i := range delays {
+				if delays[i] != tt.expectedDelays[i] {
+					t.Errorf("wanted delays %v, got %v", tt.expectedDelays, delays)
+				}
+			}
+		})
+	}
+}
+
+// Test used to test that connection errors are retried with the request body intact
+func TestClient_DoConnectionError(t *testing.T) {
+
+	attempts := 0
+	var bodies []string
+	client := NewClient(clientFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: http.StatusCreated}, nil
+	}))
+	client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/orgs/HybriStratus/teams", bytes.NewBufferString(`{"name": "test_team"}`))
+	got, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	if got.StatusCode != http.StatusCreated || attempts != 2 {
+		t.Errorf("wanted %d after 2 attempts, got %d after %d", http.StatusCreated, got.StatusCode, attempts)
+	}
+	if bodies[0] != bodies[1] {
+		t.Errorf("wanted the same body on every attempt, got %v", bodies)
+	}
+
+	// Cancelled requests are not retried
+	attempts = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Do(req.WithContext(ctx))
+	if err == nil || attempts != 1 {
+		t.Errorf("wanted a single failed attempt, got %d attempts and error %v", attempts, err)
+	}
+}
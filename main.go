@@ -2,76 +2,129 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/HybriStratus/test-github-groups/groups"
+	"github.com/HybriStratus/test-github-groups/http"
 	"github.com/HybriStratus/test-github-groups/http/net"
+	"github.com/HybriStratus/test-github-groups/http/ratelimit"
+	"github.com/HybriStratus/test-github-groups/http/retry"
 )
 
-func main() {
-	fmt.Println("Performing CRUD operations on Github teams")
+// Exit codes of the command line interface
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitNotFound     = 3
+	exitUnauthorized = 4
+	exitInvalid      = 5
+	exitRateLimited  = 6
+)
 
-	newTeam := groups.Team{
-		Name:        "iac-platfom-test-team",
-		Description: "Created a new test team",
-		Privacy:     "secret",
-	}
-	// Create HTTP client
-	client := net.Client{}
-	fmt.Printf("Creating a new Team under %s Org %v\n\n", groups.TestOrg, newTeam)
-	err := newTeam.CreateTeam(client)
-	if err != nil {
-		fmt.Printf(err.Error())
-	}
+const usage = `Usage: github-groups <command> [flags] [arguments]
 
-	newTeam.Privacy = "closed"
-	newTeam.Description = "Updated the description"
-	fmt.Printf("Updating a %s under Org %v\n\n", groups.TestOrg, newTeam)
-	err = newTeam.UpdateTeam(client)
-	if err != nil {
-		fmt.Printf(err.Error())
-	}
+Commands:
+  team create <name>          Create a team
+  team get <name>             Show the details of a team
+  team update <name>          Update the description, privacy or parent of a team
+  team ensure <name>          Create a team or update the fields that differ, safe to repeat
+                              --parent takes the slug of the parent, --parent-id its ID
+  team delete <name>          Delete a team
+  team list                   List the teams of the organization
+                              --name-prefix, --name-regexp, --privacy, --parent, --root,
+                              --min-members and --max-members select the teams listed
+  team tree                   Show the teams of the organization nested under their parents
+  team move <name> [parent]   Move a team and its child teams under parent, or to the root
+  member add <team> <user>... Add users to a team, --workers of them at once. Users already
+                              in the team keep their role unless --update-role is given
+  member remove <team> <user>...
+                              Remove users from a team, --workers of them at once
+  member list <team>          List the members of a team, --role selects member or maintainer
+  member get <team> <user>    Show the role of a user in a team and whether it is active or pending
+  member set-role <team> <user> <role>
+                              Promote or demote a member of a team to member or maintainer
+  org members                 List the members of the organization, --role selects admin or member
+  org outside-collaborators   List the users with repository access outside the organization
+  org get <user>              Show the role of a user in the organization
+  org set-role <user> <role>  Set the role of a user in the organization, admin or member
+  org remove <user>           Remove a user from the organization
+  org invite <user|email>     Invite a user to the organization, --team adds teams and --role
+                              selects direct_member, admin or billing_manager
+  org invitations             List the pending invitations of the organization
+  org cancel-invitation <id>  Cancel a pending invitation
+  sync <file>                 Reconcile the teams listed in a YAML or JSON file
 
-	fmt.Printf("Get team details for team %s\n\n", newTeam.Name)
-	err = newTeam.GetTeamDetails(client)
-	if err != nil {
-		fmt.Printf(err.Error())
-	}
+Flags accepted by every command:
+  --org string       GitHub organization (default $GITHUB_ORG)
+  --api-url string   GitHub API root, https://<host>/api/v3 for Enterprise Server (default $GITHUB_API_URL or https://api.github.com)
+  --output string    Output format, table or json (default "table")
+  --dry-run          Print the changes instead of making them
+  --debug            Log the method, URL, status, duration and request ID of every GitHub API request to stderr
 
-	addMembers := []string{"meav", "emuthusa", "rajpa", "daspano", "pkasargo", "jdwidari"}
-	for _, mem := range addMembers {
-		fmt.Printf("Adding %s to the team %s\n\n", mem, newTeam.Name)
-		err = groups.AddMemeberToTeam(client, newTeam.Name, mem, groups.DefaultRoleType)
-		if err != nil {
-			fmt.Printf(err.Error())
-			return
-		}
-	}
+Credentials, the token is read from $AUTH_TOKEN unless one of these is given:
+  --token-file string       File holding the token, read again when it changes (default $AUTH_TOKEN_FILE)
+  --token-command string    Command printing the token, like a credential helper (default $AUTH_TOKEN_COMMAND)
+  --app-id int              GitHub App ID (default $GITHUB_APP_ID)
+  --installation-id int     GitHub App installation ID (default $GITHUB_APP_INSTALLATION_ID)
+  --app-key-file string     GitHub App private key PEM file (default $GITHUB_APP_PRIVATE_KEY_FILE)
 
-	fmt.Printf("List memebers of %s\n\n", newTeam.Name)
-	err = newTeam.ListMemebersOfTeam(client)
-	if err != nil {
-		fmt.Printf(err.Error())
-		return
-	}
+Exit codes: 0 success, 1 error, 2 usage, 3 not found, 4 unauthorized or forbidden,
+5 conflict or validation failed, 6 rate limited
+`
 
-	delMembers := []string{"meav", "rajpa", "jdwidari"}
-	for _, mem := range delMembers {
-		fmt.Printf("Deleting %s from the team %s\n\n", mem, newTeam.Name)
-		err = groups.DeleteMemberFromTeam(client, newTeam.Name, mem)
-		if err != nil {
-			fmt.Printf(err.Error())
-			return
-		}
+// newHTTPClient creates the client chain used to call GitHub, tests replace it with a mock
+var newHTTPClient = func() http.Client {
+	return retry.NewClient(ratelimit.NewClient(net.Client{}))
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command in args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
 	}
 
-	fmt.Printf("List memebers of %s\n\n", newTeam.Name)
-	err = newTeam.ListMemebersOfTeam(client)
-	if err != nil {
-		fmt.Printf(err.Error())
-		return
+	switch args[0] {
+	case "team":
+		return runTeam(args[1:], stdout, stderr)
+	case "member":
+		return runMember(args[1:], stdout, stderr)
+	case "org":
+		return runOrg(args[1:], stdout, stderr)
+	case "sync":
+		return runSync(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
 	}
+	return usageError(stderr, "unknown command %q", args[0])
+}
 
-	fmt.Printf("Delete the team %s Org %s\n\n", newTeam.Name, groups.TestOrg)
-	newTeam.DeleteTeam(client)
+// usageError reports a malformed command line
+func usageError(stderr io.Writer, format string, a ...interface{}) int {
+	fmt.Fprintf(stderr, "Error: "+format+"\n\n", a...)
+	fmt.Fprint(stderr, usage)
+	return exitUsage
+}
 
+// commandError reports a failed command and maps it to an exit code
+func commandError(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "Error: %s\n", err.Error())
+	switch {
+	case groups.IsNotFound(err):
+		return exitNotFound
+	case groups.IsRateLimited(err):
+		return exitRateLimited
+	case groups.IsUnauthorized(err), groups.IsForbidden(err):
+		return exitUnauthorized
+	case groups.IsConflict(err), groups.IsValidationFailed(err):
+		return exitInvalid
+	}
+	return exitError
 }
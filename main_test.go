@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+	httpclient "github.com/HybriStratus/test-github-groups/http"
+	"github.com/HybriStratus/test-github-groups/http/fake"
+	"github.com/HybriStratus/test-github-groups/http/mock"
+	"github.com/HybriStratus/test-github-groups/http/net"
+	"github.com/HybriStratus/test-github-groups/http/plan"
+)
+
+// Test used to test the Mock Client
+type responses struct {
+	method   string
+	url      string
+	response http.Response
+}
+
+// TestRun tests the commands, their output and exit codes against the mock client
+func TestRun(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", groups.DefaultAPIURL, groups.TestOrg, "test_team")
+	teamResponse := `{"id": 5714710, "name": "test_team", "slug": "test_team", "privacy": "closed"}`
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		args           []string
+		requestClients []responses
+		expectedCode   int
+		expectedOutput string
+	}{
+		{
+			name: "Testing team get as table",
+			args: []string{"team", "get", "test_team", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    teamURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(teamResponse)),
+					},
+				},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: "ID       NAME       SLUG       PRIVACY  PARENT  DESCRIPTION\n5714710  test_team  test_team  closed           \n",
+		},
+		{
+			name: "Testing team get as json",
+			args: []string{"team", "get", "--output", "json", "--org", groups.TestOrg, "test_team"},
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    teamURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(teamResponse)),
+					},
+				},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: `"slug": "test_team"`,
+		},
+		{
+			name: "Testing missing team",
+			args: []string{"team", "delete", "test_team", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      teamURL,
+					response: http.Response{StatusCode: http.StatusNotFound},
+				},
+			},
+			expectedCode: exitNotFound,
+		},
+		{
+			name: "Testing team list filtered by parent",
+			args: []string{"team", "list", "--parent", "platform", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams", groups.DefaultAPIURL, groups.TestOrg),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body: ioutil.NopCloser(strings.NewReader(`[{"id": 1, "name": "platform", "slug": "platform"},
+							{"id": 2, "name": "backend", "slug": "backend", "parent": {"id": 1, "slug": "platform"}}]`)),
+					},
+				},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: "ID  NAME     SLUG     PRIVACY  PARENT    DESCRIPTION\n2   backend  backend           platform  \n",
+		},
+		{
+			name:         "Testing invalid team list regexp",
+			args:         []string{"team", "list", "--name-regexp", "(", "--org", groups.TestOrg},
+			expectedCode: exitUsage,
+		},
+		{
+			name: "Testing team ensure of an unchanged team",
+			args: []string{"team", "ensure", "test_team", "--privacy", "closed", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    teamURL,
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(teamResponse)),
+					},
+				},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: "5714710  test_team  test_team  unchanged",
+		},
+		{
+			name: "Testing team tree",
+			args: []string{"team", "tree", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    fmt.Sprintf("%s/orgs/%s/teams", groups.DefaultAPIURL, groups.TestOrg),
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body: ioutil.NopCloser(strings.NewReader(`[{"id": 1, "name": "platform", "slug": "platform"},
+							{"id": 2, "name": "backend", "slug": "backend", "parent": {"id": 1}}]`)),
+					},
+				},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: "NAME       SLUG      ID\nplatform   platform  1\n  backend  backend   2\n",
+		},
+		{
+			name: "Testing forbidden member removal",
+			args: []string{"member", "remove", "test_team", "test_user", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      teamURL + "/memberships/test_user",
+					response: http.Response{StatusCode: http.StatusForbidden},
+				},
+			},
+			expectedCode: exitUnauthorized,
+		},
+		{
+			name:           "Testing dry-run member add",
+			args:           []string{"member", "add", "test_team", "test_user", "--role", "maintainer", "--dry-run", "--org", groups.TestOrg},
+			expectedCode:   exitOK,
+			expectedOutput: "PUT    " + teamURL + "/memberships/test_user\n         {\"role\":\"maintainer\"}",
+		},
+		{
+			name: "Testing member get of a pending membership",
+			args: []string{"member", "get", "test_team", "test_user", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method: http.MethodGet,
+					url:    teamURL + "/memberships/test_user",
+					response: http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(`{"role": "maintainer", "state": "pending"}`)),
+					},
+				},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: "test_team  test_user  maintainer  pending\n",
+		},
+		{
+			name: "Testing bulk member removal with a failure",
+			args: []string{"member", "remove", "test_team", "alice", "ghost", "--workers", "1", "--org", groups.TestOrg},
+			requestClients: []responses{
+				{
+					method:   http.MethodDelete,
+					url:      teamURL + "/memberships/alice",
+					response: http.Response{StatusCode: http.StatusNoContent},
+				},
+				{
+					method:   http.MethodDelete,
+					url:      teamURL + "/memberships/ghost",
+					response: http.Response{StatusCode: http.StatusNotFound},
+				},
+			},
+			expectedCode:   exitError,
+			expectedOutput: "alice  ok",
+		},
+		{
+			name:           "Testing dry-run org invitation by email",
+			args:           []string{"org", "invite", "newcomer@example.com", "--dry-run", "--org", groups.TestOrg},
+			expectedCode:   exitOK,
+			expectedOutput: `{"email":"newcomer@example.com","role":"direct_member"}`,
+		},
+		{
+			name:         "Testing missing arguments",
+			args:         []string{"member", "add", "test_team", "--org", groups.TestOrg},
+			expectedCode: exitUsage,
+		},
+		{
+			name:         "Testing unknown command",
+			args:         []string{"teams"},
+			expectedCode: exitUsage,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Create the mock Client
+			mockClient := mock.Client{}
+			for _, response := range tt.requestClients {
+				mockClient.SetResponses(response.method, response.url, response.response)
+			}
+			newHTTPClient = func() httpclient.Client { return mockClient }
+
+			var stdout, stderr bytes.Buffer
+			got := run(tt.args, &stdout, &stderr)
+			if got != tt.expectedCode {
+				t.Errorf("wanted exit code %d, got %d: %s", tt.expectedCode, got, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.expectedOutput) {
+				t.Errorf("wanted output %q, got %q", tt.expectedOutput, stdout.String())
+			}
+		})
+	}
+}
+
+// failingWriter fails every write, like a closed pipe
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("broken pipe")
+}
+
+// TestRunWriteError tests that commands fail when their result can not be written
+func TestRunWriteError(t *testing.T) {
+
+	orgURL := fmt.Sprintf("%s/orgs/%s", groups.DefaultAPIURL, groups.TestOrg)
+
+	// Create your table test
+	tests := []struct {
+		name string
+		args []string
+		url  string
+		body string
+	}{
+		{
+			name: "Testing team get",
+			args: []string{"team", "get", "test_team"},
+			url:  orgURL + "/teams/test_team",
+			body: `{"id": 1, "name": "test_team", "slug": "test_team"}`,
+		},
+		{
+			name: "Testing team tree",
+			args: []string{"team", "tree"},
+			url:  orgURL + "/teams",
+			body: `[{"id": 1, "name": "test_team", "slug": "test_team"}]`,
+		},
+		{
+			name: "Testing member list",
+			args: []string{"member", "list", "test_team"},
+			url:  orgURL + "/teams/test_team/members",
+			body: `[{"login": "alice"}]`,
+		},
+		{
+			name: "Testing org invitations",
+			args: []string{"org", "invitations"},
+			url:  orgURL + "/invitations",
+			body: `[{"id": 1, "login": "alice"}]`,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := mock.Client{}
+			mockClient.SetResponses(http.MethodGet, tt.url, http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(tt.body)),
+			})
+			newHTTPClient = func() httpclient.Client { return mockClient }
+
+			var stderr bytes.Buffer
+			args := append(tt.args, "--output", "json", "--org", groups.TestOrg)
+			if got := run(args, failingWriter{}, &stderr); got != exitError {
+				t.Errorf("wanted exit code %d, got %d: %s", exitError, got, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), "broken pipe") {
+				t.Errorf("wanted the write error reported, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+// TestRunDryRunJSON tests that dry-run commands write their result and plan as a single JSON document
+func TestRunDryRunJSON(t *testing.T) {
+
+	teamURL := fmt.Sprintf("%s/orgs/%s/teams/%s", groups.DefaultAPIURL, groups.TestOrg, "test_team")
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		args           []string
+		expectedResult string
+		expectedChange plan.Change
+	}{
+		{
+			name:           "Testing dry-run member add",
+			args:           []string{"member", "add", "test_team", "test_user", "--role", "maintainer"},
+			expectedResult: `{"url":"","role":"maintainer","state":""}`,
+			expectedChange: plan.Change{Method: http.MethodPut, URL: teamURL + "/memberships/test_user"},
+		},
+		{
+			name:           "Testing dry-run team delete",
+			args:           []string{"team", "delete", "test_team"},
+			expectedResult: `{"status":"deleted","team":"test_team"}`,
+			expectedChange: plan.Change{Method: http.MethodDelete, URL: teamURL},
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newHTTPClient = func() httpclient.Client { return mock.Client{} }
+
+			var stdout, stderr bytes.Buffer
+			args := append(tt.args, "--dry-run", "--output", "json", "--org", groups.TestOrg)
+			if got := run(args, &stdout, &stderr); got != exitOK {
+				t.Fatalf("wanted exit code %d, got %d: %s", exitOK, got, stderr.String())
+			}
+
+			var document struct {
+				Result json.RawMessage `json:"result"`
+				Plan   struct {
+					Changes []plan.Change `json:"changes"`
+				} `json:"plan"`
+			}
+			decoder := json.NewDecoder(&stdout)
+			if err := decoder.Decode(&document); err != nil {
+				t.Fatalf("wanted a JSON document, got %v", err)
+			}
+			if err := decoder.Decode(&json.RawMessage{}); err != io.EOF {
+				t.Errorf("wanted a single JSON document, got %v after it", err)
+			}
+
+			var result bytes.Buffer
+			json.Compact(&result, document.Result)
+			if result.String() != tt.expectedResult {
+				t.Errorf("wanted result %s, got %s", tt.expectedResult, result.String())
+			}
+			changes := document.Plan.Changes
+			if len(changes) != 1 || changes[0].Method != tt.expectedChange.Method || changes[0].URL != tt.expectedChange.URL {
+				t.Errorf("wanted %v planned, got %v", tt.expectedChange, changes)
+			}
+		})
+	}
+}
+
+// TestRunFake tests a sequence of commands against a fake GitHub
+func TestRunFake(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddOrg(groups.TestOrg)
+	server.AddOrgMember(groups.TestOrg, "alice", "member")
+	newHTTPClient = func() httpclient.Client { return net.Client{} }
+
+	// Create your table test
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedOutput string
+		expectedLog    string
+	}{
+		{
+			name:         "Testing create team",
+			args:         []string{"team", "create", "Test Team"},
+			expectedCode: exitOK,
+		},
+		{
+			name:         "Testing create team with a taken name",
+			args:         []string{"team", "create", "test team"},
+			expectedCode: exitInvalid,
+		},
+		{
+			name:           "Testing add member",
+			args:           []string{"member", "add", "test-team", "alice"},
+			expectedCode:   exitOK,
+			expectedOutput: "alice",
+		},
+		{
+			name:           "Testing list members",
+			args:           []string{"member", "list", "test-team", "--output", "json"},
+			expectedCode:   exitOK,
+			expectedOutput: `"login": "alice"`,
+		},
+		{
+			name:         "Testing delete team",
+			args:         []string{"team", "delete", "test-team"},
+			expectedCode: exitOK,
+		},
+		{
+			name:         "Testing debug log",
+			args:         []string{"team", "list", "--debug"},
+			expectedCode: exitOK,
+			expectedLog:  "DEBUG github request method=GET url=" + server.URL + "/orgs/" + groups.TestOrg + "/teams status=200",
+		},
+		{
+			name:         "Testing get deleted team",
+			args:         []string{"team", "get", "test-team"},
+			expectedCode: exitNotFound,
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append(tt.args, "--org", groups.TestOrg, "--api-url", server.URL)
+			got := run(args, &stdout, &stderr)
+			if got != tt.expectedCode {
+				t.Errorf("wanted exit code %d, got %d: %s", tt.expectedCode, got, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.expectedOutput) {
+				t.Errorf("wanted output %q, got %q", tt.expectedOutput, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.expectedLog) {
+				t.Errorf("wanted log %q, got %q", tt.expectedLog, stderr.String())
+			}
+		})
+	}
+}
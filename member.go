@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+)
+
+// runMember executes the member subcommands
+func runMember(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return usageError(stderr, "missing member subcommand")
+	}
+
+	fs, opts := newFlagSet("member " + args[0])
+	var role string
+	bulk := &groups.BulkOptions{}
+	if args[0] == "add" || args[0] == "remove" {
+		fs.IntVar(&bulk.Workers, "workers", groups.DefaultWorkers, "Requests sent at once when several users are given")
+	}
+	switch args[0] {
+	case "add":
+		fs.StringVar(&role, "role", groups.DefaultRoleType, "Role in the team, member or maintainer")
+		fs.BoolVar(&bulk.UpdateRole, "update-role", false, "Change the role of users already in the team when several users are given")
+	case "list":
+		fs.StringVar(&role, "role", "", "Only members with the role, member, maintainer or all")
+	}
+	positional, err := parseArgs(fs, opts, args[1:])
+	if err != nil {
+		return usageError(stderr, "%s", err.Error())
+	}
+
+	ctx := context.Background()
+	service, err := opts.service(stderr)
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	out := opts.resultWriter(stdout)
+	// Several users are added or removed at once and reported one by one
+	if (args[0] == "add" || args[0] == "remove") && len(positional) > 2 {
+		var report *groups.BulkReport
+		if args[0] == "add" {
+			report, err = service.AddMembers(ctx, positional[0], positional[1:], role, bulk)
+			if err != nil {
+				return commandError(stderr, err)
+			}
+		} else {
+			report = service.RemoveMembers(ctx, positional[0], positional[1:], bulk)
+		}
+		return writeBulkReport(stdout, stderr, opts, report)
+	}
+
+	switch args[0] {
+	case "add":
+		if err := checkArgs(positional, "team", "user"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var membership *groups.Membership
+		membership, err = service.AddMemeberToTeam(ctx, positional[0], positional[1], role)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeOutput(out, opts.output, membership, func(tw io.Writer) {
+			fmt.Fprintln(tw, "TEAM\tUSER\tROLE\tSTATE")
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", positional[0], positional[1], membership.Role, membership.State)
+		})
+
+	case "remove":
+		if err := checkArgs(positional, "team", "user"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		err = service.DeleteMemberFromTeam(ctx, positional[0], positional[1])
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeMessage(out, opts.output, "removed", map[string]string{"team": positional[0], "user": positional[1]},
+			fmt.Sprintf("Removed %s from team %s", positional[1], positional[0]))
+
+	case "list":
+		if err := checkArgs(positional, "team"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var members []groups.User
+		members, err = service.ListMemebersOfTeamByRole(ctx, positional[0], role, nil)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeUsers(out, opts.output, members)
+
+	case "get", "set-role":
+		names := []string{"team", "user"}
+		if args[0] == "set-role" {
+			names = append(names, "role")
+		}
+		if err := checkArgs(positional, names...); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var membership *groups.Membership
+		if args[0] == "get" {
+			membership, err = service.GetMembership(ctx, positional[0], positional[1])
+		} else {
+			membership, err = service.SetMemberRole(ctx, positional[0], positional[1], positional[2])
+		}
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeOutput(out, opts.output, membership, func(tw io.Writer) {
+			fmt.Fprintln(tw, "TEAM\tUSER\tROLE\tSTATE")
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", positional[0], positional[1], membership.Role, membership.State)
+		})
+
+	default:
+		return usageError(stderr, "unknown member subcommand %q", args[0])
+	}
+
+	if err == nil {
+		err = opts.writePlan(stdout)
+	}
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	return exitOK
+}
+
+// writeBulkReport writes the result of every user and fails when any user failed
+func writeBulkReport(stdout, stderr io.Writer, opts *options, report *groups.BulkReport) int {
+	err := writeOutput(opts.resultWriter(stdout), opts.output, report, func(tw io.Writer) {
+		fmt.Fprintln(tw, "USER\tSTATUS\tROLE\tERROR")
+		for _, result := range report.Results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.User, result.Status, result.Role, result.Error)
+		}
+	})
+	if err == nil {
+		err = opts.writePlan(stdout)
+	}
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	if failed := report.Failed(); len(failed) > 0 {
+		fmt.Fprintf(stderr, "Error: %d of %d users failed\n", len(failed), len(report.Results))
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+)
+
+// runOrg executes the org subcommands
+func runOrg(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return usageError(stderr, "missing org subcommand")
+	}
+
+	fs, opts := newFlagSet("org " + args[0])
+	var role string
+	var teams stringsFlag
+	switch args[0] {
+	case "members":
+		fs.StringVar(&role, "role", "", "Only members with the role, admin or member")
+	case "invite":
+		fs.StringVar(&role, "role", groups.InvitationRoleDirectMember, "Role of the invitee, direct_member, admin or billing_manager")
+		fs.Var(&teams, "team", "Team the invitee joins, repeat for several teams")
+	}
+	positional, err := parseArgs(fs, opts, args[1:])
+	if err != nil {
+		return usageError(stderr, "%s", err.Error())
+	}
+
+	ctx := context.Background()
+	service, err := opts.service(stderr)
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	out := opts.resultWriter(stdout)
+	switch args[0] {
+	case "members", "outside-collaborators":
+		if err := checkArgs(positional); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var users []groups.User
+		if args[0] == "members" {
+			users, err = service.ListOrgMembers(ctx, role, nil)
+		} else {
+			users, err = service.ListOutsideCollaborators(ctx, nil)
+		}
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeUsers(out, opts.output, users)
+
+	case "get", "set-role":
+		names := []string{"user"}
+		if args[0] == "set-role" {
+			names = append(names, "role")
+		}
+		if err := checkArgs(positional, names...); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var membership *groups.OrgMembership
+		if args[0] == "get" {
+			membership, err = service.GetOrgMembership(ctx, positional[0])
+		} else {
+			membership, err = service.SetOrgMembership(ctx, positional[0], positional[1])
+		}
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeOutput(out, opts.output, membership, func(tw io.Writer) {
+			fmt.Fprintln(tw, "USER\tROLE\tSTATE")
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", positional[0], membership.Role, membership.State)
+		})
+
+	case "remove":
+		if err := checkArgs(positional, "user"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		err = service.RemoveOrgMembership(ctx, positional[0])
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeMessage(out, opts.output, "removed", map[string]string{"org": opts.org, "user": positional[0]},
+			fmt.Sprintf("Removed %s from org %s", positional[0], opts.org))
+
+	case "invite":
+		if err := checkArgs(positional, "user or email"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		request := &groups.InvitationRequest{Role: role, TeamSlugs: teams}
+		if strings.Contains(positional[0], "@") {
+			request.Email = positional[0]
+		} else {
+			request.Login = positional[0]
+		}
+		var invitation *groups.Invitation
+		invitation, err = service.CreateInvitation(ctx, request)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeInvitations(out, opts.output, invitation, []groups.Invitation{*invitation})
+
+	case "invitations":
+		if err := checkArgs(positional); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var invitations []groups.Invitation
+		invitations, err = service.ListInvitations(ctx, nil)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeInvitations(out, opts.output, invitations, invitations)
+
+	case "cancel-invitation":
+		if err := checkArgs(positional, "id"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var id int
+		id, err = strconv.Atoi(positional[0])
+		if err != nil {
+			return usageError(stderr, "invalid invitation id %q", positional[0])
+		}
+		err = service.CancelInvitation(ctx, id)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeMessage(out, opts.output, "cancelled", map[string]string{"org": opts.org, "invitation": positional[0]},
+			fmt.Sprintf("Cancelled invitation %d", id))
+
+	default:
+		return usageError(stderr, "unknown org subcommand %q", args[0])
+	}
+
+	if err == nil {
+		err = opts.writePlan(stdout)
+	}
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	return exitOK
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+)
+
+// writeOutput writes v as indented JSON, or as a table rendered by writeTable
+func writeOutput(w io.Writer, format string, v interface{}, writeTable func(tw io.Writer)) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	writeTable(tw)
+	return tw.Flush()
+}
+
+// writeTeams writes teams in the output format
+func writeTeams(w io.Writer, format string, v interface{}, teams []groups.TeamDetails) error {
+	return writeOutput(w, format, v, func(tw io.Writer) {
+		fmt.Fprintln(tw, "ID\tNAME\tSLUG\tPRIVACY\tPARENT\tDESCRIPTION")
+		for _, team := range teams {
+			parent := ""
+			if team.Parent != nil {
+				parent = team.Parent.Slug
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", team.ID, team.Name, team.Slug, team.Privacy, parent, team.Description)
+		}
+	})
+}
+
+// writeTeamTree writes the team tree in the output format, indenting the child teams
+func writeTeamTree(w io.Writer, format string, roots []*groups.TeamNode) error {
+	return writeOutput(w, format, roots, func(tw io.Writer) {
+		fmt.Fprintln(tw, "NAME\tSLUG\tID")
+		var walk func(nodes []*groups.TeamNode, indent string)
+		walk = func(nodes []*groups.TeamNode, indent string) {
+			for _, node := range nodes {
+				fmt.Fprintf(tw, "%s%s\t%s\t%d\n", indent, node.Name, node.Slug, node.ID)
+				walk(node.Children, indent+"  ")
+			}
+		}
+		walk(roots, "")
+	})
+}
+
+// writeUsers writes users in the output format
+func writeUsers(w io.Writer, format string, users []groups.User) error {
+	return writeOutput(w, format, users, func(tw io.Writer) {
+		fmt.Fprintln(tw, "LOGIN\tID\tTYPE")
+		for _, user := range users {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", user.Login, user.ID, user.Type)
+		}
+	})
+}
+
+// writeInvitations writes invitations in the output format
+func writeInvitations(w io.Writer, format string, v interface{}, invitations []groups.Invitation) error {
+	return writeOutput(w, format, v, func(tw io.Writer) {
+		fmt.Fprintln(tw, "ID\tLOGIN\tEMAIL\tROLE\tTEAMS\tCREATED")
+		for _, invitation := range invitations {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\n", invitation.ID, invitation.Login, invitation.Email,
+				invitation.Role, invitation.TeamCount, invitation.CreatedAt)
+		}
+	})
+}
+
+// writeMessage writes a status message for commands without a result
+func writeMessage(w io.Writer, format string, status string, fields map[string]string, message string) error {
+	result := map[string]string{"status": status}
+	for key, value := range fields {
+		result[key] = value
+	}
+	return writeOutput(w, format, result, func(tw io.Writer) {
+		fmt.Fprintln(tw, message)
+	})
+}
@@ -0,0 +1,395 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+)
+
+// Actions of the changes made by the Reconciler
+const (
+	ActionCreateTeam   = "create-team"
+	ActionUpdateTeam   = "update-team"
+	ActionAddMember    = "add-member"
+	ActionRemoveMember = "remove-member"
+	ActionChangeRole   = "change-role"
+
+	ActionAddRepo              = "add-repo"
+	ActionChangeRepoPermission = "change-repo-permission"
+)
+
+// Change is a single change made to the organization
+type Change struct {
+	Team   string `json:"team"`
+	Action string `json:"action"`
+	Target string `json:"target,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Summary lists the changes made by a reconciliation
+type Summary struct {
+	Changes []Change `json:"changes"`
+}
+
+// String renders the summary with one change per line
+func (summary *Summary) String() string {
+	if len(summary.Changes) == 0 {
+		return "No changes\n"
+	}
+	var buf bytes.Buffer
+	for _, change := range summary.Changes {
+		fmt.Fprintf(&buf, "%s %s", change.Action, change.Team)
+		if change.Target != "" {
+			fmt.Fprintf(&buf, " %s", change.Target)
+		}
+		if change.Detail != "" {
+			fmt.Fprintf(&buf, " (%s)", change.Detail)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// add records a change in the summary
+func (summary *Summary) add(team, action, target, detail string) {
+	summary.Changes = append(summary.Changes, Change{Team: team, Action: action, Target: target, Detail: detail})
+}
+
+// Reconciler brings the teams of an organization to a desired state
+type Reconciler struct {
+	Service *groups.Service
+}
+
+// NewReconciler creates a Reconciler operating through service
+func NewReconciler(service *groups.Service) *Reconciler {
+	return &Reconciler{Service: service}
+}
+
+// Apply diffs every team of state against GitHub and makes only the required changes.
+// It stops at the first failure and returns the changes made until then.
+// Repositories are granted the permission of their spec, pull when it has none, and
+// repositories the team has but which are not listed are left as they are.
+// With a service built on a plan.Client the changes are planned instead of made, teams
+// nested under a planned team are then planned without parent ID and the summary names
+// their parent
+func (r *Reconciler) Apply(ctx context.Context, state *DesiredState) (*Summary, error) {
+	summary := &Summary{}
+	specs, err := orderByParent(state.Teams)
+	if err != nil {
+		return summary, err
+	}
+
+	teamIDs := map[string]int{}
+	for _, spec := range specs {
+		details, created, err := r.reconcileTeam(ctx, spec, teamIDs, summary)
+		if err != nil {
+			return summary, err
+		}
+		// Teams created in plan mode are echoed without an ID, they are kept with ID 0
+		teamIDs[strings.ToLower(spec.Name)] = details.ID
+
+		team := teamSlug(spec, details)
+		err = r.reconcileMembers(ctx, spec, team, created, summary)
+		if err != nil {
+			return summary, err
+		}
+
+		err = r.reconcileRepos(ctx, spec, team, created, summary)
+		if err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// reconcileTeam creates the team of spec or updates the fields that differ, it reports
+// whether the team was created
+func (r *Reconciler) reconcileTeam(ctx context.Context, spec TeamSpec, teamIDs map[string]int, summary *Summary) (*groups.TeamDetails, bool, error) {
+	parentID, plannedParent, err := r.parentID(ctx, spec, teamIDs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	team := &groups.Team{
+		Name:         spec.Name,
+		Description:  spec.Description,
+		Privacy:      spec.Privacy,
+		ParentTeamID: parentID,
+	}
+	details, result, err := r.Service.EnsureTeam(ctx, team)
+	if err != nil {
+		return nil, false, err
+	}
+	fields := result.Fields
+	if plannedParent && result.Action != groups.TeamCreated {
+		// The ID of a planned parent is unknown, so EnsureTeam could not compare it
+		fields = append(fields, "parent")
+	}
+	switch {
+	case result.Action == groups.TeamCreated:
+		detail := ""
+		if spec.Parent != "" {
+			detail = "parent " + spec.Parent
+		}
+		summary.add(spec.Name, ActionCreateTeam, "", detail)
+	case len(fields) > 0:
+		summary.add(spec.Name, ActionUpdateTeam, "", strings.Join(fields, ", "))
+	}
+	return details, result.Action == groups.TeamCreated, nil
+}
+
+// teamSlug returns the slug addressing the team of spec. A team created in plan mode is
+// echoed without a slug, it is addressed by the name of spec instead
+func teamSlug(spec TeamSpec, details *groups.TeamDetails) string {
+	if details.Slug != "" {
+		return details.Slug
+	}
+	return spec.Name
+}
+
+// parentID resolves the ID of the parent of spec, either reconciled earlier or read from GitHub.
+// It reports whether the parent is only planned, a team created in plan mode has no ID yet
+func (r *Reconciler) parentID(ctx context.Context, spec TeamSpec, teamIDs map[string]int) (int, bool, error) {
+	if spec.Parent == "" {
+		return 0, false, nil
+	}
+	if id, ok := teamIDs[strings.ToLower(spec.Parent)]; ok {
+		return id, id == 0, nil
+	}
+	parent, err := r.Service.GetTeamDetails(ctx, spec.Parent)
+	if err != nil {
+		return 0, false, err
+	}
+	return parent.ID, false, nil
+}
+
+// reconcileMembers adds the missing maintainers and members of spec, changes the role of
+// the ones in the wrong role and removes everyone else. Users with a pending membership
+// count as members, so they are neither added again nor left behind
+func (r *Reconciler) reconcileMembers(ctx context.Context, spec TeamSpec, team string, created bool, summary *Summary) error {
+	if spec.Members == nil && spec.Maintainers == nil {
+		return nil
+	}
+
+	desired := map[string]string{}
+	var order []string
+	for _, user := range spec.Members {
+		desired[strings.ToLower(user)] = groups.RoleMember
+		order = append(order, user)
+	}
+	for _, user := range spec.Maintainers {
+		if _, ok := desired[strings.ToLower(user)]; !ok {
+			order = append(order, user)
+		}
+		desired[strings.ToLower(user)] = groups.RoleMaintainer
+	}
+
+	members, err := r.Service.ListMemebersOfTeam(ctx, team, nil)
+	if created && groups.IsNotFound(err) {
+		// A team created in plan mode does not exist yet, so it has no members
+		members, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	current := map[string]string{}
+	var present []string
+	for _, member := range members {
+		current[strings.ToLower(member.Login)] = groups.RoleMember
+		present = append(present, member.Login)
+	}
+	err = r.readMaintainers(ctx, team, desired, current)
+	if err != nil {
+		return err
+	}
+	pending, err := r.readPending(ctx, team, created, desired, current)
+	if err != nil {
+		return err
+	}
+	present = append(present, pending...)
+
+	for _, user := range order {
+		role := desired[strings.ToLower(user)]
+		currentRole, ok := current[strings.ToLower(user)]
+		if ok && currentRole == role {
+			continue
+		}
+		_, err = r.Service.AddMemeberToTeam(ctx, team, user, role)
+		if err != nil {
+			return err
+		}
+		if ok {
+			summary.add(spec.Name, ActionChangeRole, user, role)
+		} else {
+			summary.add(spec.Name, ActionAddMember, user, role)
+		}
+	}
+
+	var extra []string
+	for _, user := range present {
+		if _, ok := desired[strings.ToLower(user)]; !ok {
+			extra = append(extra, user)
+		}
+	}
+	sort.Strings(extra)
+	for _, user := range extra {
+		err = r.Service.DeleteMemberFromTeam(ctx, team, user)
+		if err != nil {
+			return err
+		}
+		summary.add(spec.Name, ActionRemoveMember, user, "")
+	}
+	return nil
+}
+
+// readMaintainers marks the maintainers in current, the roles of current members only
+// matter when one of them is desired, so the listing is skipped otherwise
+func (r *Reconciler) readMaintainers(ctx context.Context, team string, desired, current map[string]string) error {
+	needed := false
+	for user := range current {
+		if _, ok := desired[user]; ok {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	maintainers, err := r.Service.ListMemebersOfTeamByRole(ctx, team, groups.RoleMaintainer, nil)
+	if err != nil {
+		return err
+	}
+	for _, maintainer := range maintainers {
+		current[strings.ToLower(maintainer.Login)] = groups.RoleMaintainer
+	}
+	return nil
+}
+
+// readPending adds the users whose membership is pending until they join the organization
+// to current and returns their logins, GitHub does not list them as members. The role of
+// a pending user is only read when the user is desired
+func (r *Reconciler) readPending(ctx context.Context, team string, created bool, desired, current map[string]string) ([]string, error) {
+	invitations, err := r.Service.ListTeamInvitations(ctx, team, nil)
+	if created && groups.IsNotFound(err) {
+		// A team created in plan mode does not exist yet, so it has no invitations
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, invitation := range invitations {
+		key := strings.ToLower(invitation.Login)
+		// Invitations sent by email have no login until they are accepted
+		if invitation.Login == "" {
+			continue
+		}
+		if _, ok := current[key]; ok {
+			continue
+		}
+		pending = append(pending, invitation.Login)
+		current[key] = groups.RoleMember
+		if _, ok := desired[key]; !ok {
+			continue
+		}
+		membership, err := r.Service.GetMembership(ctx, team, invitation.Login)
+		if err != nil {
+			return nil, err
+		}
+		current[key] = membership.Role
+	}
+	return pending, nil
+}
+
+// reconcileRepos grants the team the repositories of spec it cannot access yet and
+// changes the permission of the ones where it differs
+func (r *Reconciler) reconcileRepos(ctx context.Context, spec TeamSpec, team string, created bool, summary *Summary) error {
+	if len(spec.Repos) == 0 {
+		return nil
+	}
+
+	repos, err := r.Service.ListTeamRepos(ctx, team, nil)
+	if created && groups.IsNotFound(err) {
+		// A team created in plan mode does not exist yet, so it has no repositories
+		repos, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	current := map[string]string{}
+	for i := range repos {
+		current[strings.ToLower(repos[i].FullName)] = repos[i].Permission()
+	}
+
+	for _, repo := range spec.Repos {
+		permission := repo.Permission
+		if permission == "" {
+			permission = groups.PermissionPull
+		}
+		currentPermission, ok := current[strings.ToLower(repo.Name)]
+		if ok && (repo.Permission == "" || currentPermission == permission) {
+			continue
+		}
+		owner, name := splitRepoName(repo.Name)
+		err = r.Service.SetTeamRepoPermission(ctx, team, owner, name, permission)
+		if err != nil {
+			return err
+		}
+		if ok {
+			summary.add(spec.Name, ActionChangeRepoPermission, repo.Name, permission)
+		} else {
+			summary.add(spec.Name, ActionAddRepo, repo.Name, permission)
+		}
+	}
+	return nil
+}
+
+// splitRepoName splits the full name owner/repo of a repository, validate made sure it has both
+func splitRepoName(fullName string) (owner, repo string) {
+	parts := strings.SplitN(fullName, "/", 2)
+	return parts[0], parts[1]
+}
+
+// orderByParent sorts specs so every team comes after its parent and rejects cycles
+func orderByParent(specs []TeamSpec) ([]TeamSpec, error) {
+	byName := map[string]TeamSpec{}
+	for _, spec := range specs {
+		byName[strings.ToLower(spec.Name)] = spec
+	}
+
+	const visiting, done = 1, 2
+	state := map[string]int{}
+	ordered := make([]TeamSpec, 0, len(specs))
+	var visit func(spec TeamSpec) error
+	visit = func(spec TeamSpec) error {
+		key := strings.ToLower(spec.Name)
+		switch state[key] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("Error in desired state, team %s is part of a parent cycle", spec.Name)
+		}
+		state[key] = visiting
+		if parent, ok := byName[strings.ToLower(spec.Parent)]; ok && spec.Parent != "" {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[key] = done
+		ordered = append(ordered, spec)
+		return nil
+	}
+
+	for _, spec := range specs {
+		if err := visit(spec); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,407 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+	"github.com/HybriStratus/test-github-groups/http/fake"
+	"github.com/HybriStratus/test-github-groups/http/mock"
+	"github.com/HybriStratus/test-github-groups/http/net"
+	"github.com/HybriStratus/test-github-groups/http/plan"
+)
+
+// Test used to test the Mock Client
+type responses struct {
+	method   string
+	url      string
+	response http.Response
+}
+
+// TestParse tests parsing of YAML and JSON desired state
+func TestParse(t *testing.T) {
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		data     string
+		expected *DesiredState
+		err      error
+	}{
+		{
+			name: "Testing YAML desired state",
+			data: `
+teams:
+  - name: platform
+    privacy: closed
+    maintainers: [alice]
+    repos:
+      - name: HybriStratus/infra
+        permission: push
+`,
+			expected: &DesiredState{Teams: []TeamSpec{{
+				Name:        "platform",
+				Privacy:     "closed",
+				Maintainers: []string{"alice"},
+				Repos:       []RepoSpec{{Name: "HybriStratus/infra", Permission: "push"}},
+			}}},
+		},
+		{
+			name:     "Testing JSON desired state",
+			data:     `{"teams": [{"name": "platform", "parent": "engineering", "members": ["bob"]}]}`,
+			expected: &DesiredState{Teams: []TeamSpec{{Name: "platform", Parent: "engineering", Members: []string{"bob"}}}},
+		},
+		{
+			name: "Testing duplicated team",
+			data: `{"teams": [{"name": "platform"}, {"name": "Platform"}]}`,
+			err:  fmt.Errorf("Error in desired state, team Platform is listed twice"),
+		},
+		{
+			name: "Testing repository without owner",
+			data: `{"teams": [{"name": "platform", "repos": [{"name": "infra"}]}]}`,
+			err:  fmt.Errorf("Error in desired state, repository infra of team platform is not named owner/repo"),
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := Parse([]byte(tt.data))
+			if fmt.Sprint(err) != fmt.Sprint(tt.err) {
+				t.Fatalf("wanted %v, got %v", tt.err, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("wanted %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestApply tests that the reconciler only makes the required changes
+func TestApply(t *testing.T) {
+
+	teamsURL := fmt.Sprintf("%s/orgs/%s/teams", groups.DefaultAPIURL, groups.TestOrg)
+	state, err := Parse([]byte(`
+teams:
+  - name: platform-sre
+    parent: platform
+    members: [carol]
+  - name: platform
+    description: Platform team
+    privacy: closed
+    maintainers: [alice]
+    members: [bob]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestClients := []responses{
+		{
+			method: http.MethodGet,
+			url:    teamsURL + "/platform",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 1, "name": "platform", "slug": "platform", "description": "old", "privacy": "closed"}`)),
+			},
+		},
+		{
+			method: http.MethodPatch,
+			url:    teamsURL + "/platform",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 1, "name": "platform", "slug": "platform", "description": "Platform team", "privacy": "closed"}`)),
+			},
+		},
+		{
+			method: http.MethodGet,
+			url:    teamsURL + "/platform/members",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`[{"login": "alice"}, {"login": "dave"}]`)),
+			},
+		},
+		{
+			method: http.MethodGet,
+			url:    teamsURL + "/platform/members?role=maintainer",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`[]`)),
+			},
+		},
+		{
+			method: http.MethodGet,
+			url:    teamsURL + "/platform/invitations",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`[]`)),
+			},
+		},
+		{
+			method: http.MethodPut,
+			url:    teamsURL + "/platform/memberships/alice",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "maintainer", "state": "active"}`)),
+			},
+		},
+		{
+			method: http.MethodPut,
+			url:    teamsURL + "/platform/memberships/bob",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "active"}`)),
+			},
+		},
+		{
+			method:   http.MethodDelete,
+			url:      teamsURL + "/platform/memberships/dave",
+			response: http.Response{StatusCode: http.StatusNoContent},
+		},
+		{
+			method:   http.MethodGet,
+			url:      teamsURL + "/platform-sre",
+			response: http.Response{StatusCode: http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost,
+			url:    teamsURL,
+			response: http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 2, "name": "platform-sre", "slug": "platform-sre", "parent": {"id": 1}}`)),
+			},
+		},
+		{
+			method: http.MethodGet,
+			url:    teamsURL + "/platform-sre/members",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`[]`)),
+			},
+		},
+		{
+			method: http.MethodGet,
+			url:    teamsURL + "/platform-sre/invitations",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`[]`)),
+			},
+		},
+		{
+			method: http.MethodPut,
+			url:    teamsURL + "/platform-sre/memberships/carol",
+			response: http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ConvertBytesToIoReadCloser([]byte(`{"role": "member", "state": "pending"}`)),
+			},
+		},
+	}
+
+	// Create the mock Client
+	mockClient := mock.Client{}
+	for _, response := range requestClients {
+		mockClient.SetResponses(response.method, response.url, response.response)
+	}
+
+	reconciler := NewReconciler(groups.NewService(mockClient, groups.TestOrg, ""))
+	summary, err := reconciler.Apply(context.Background(), state)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+
+	expected := []Change{
+		{Team: "platform", Action: ActionUpdateTeam, Detail: "description"},
+		{Team: "platform", Action: ActionAddMember, Target: "bob", Detail: "member"},
+		{Team: "platform", Action: ActionChangeRole, Target: "alice", Detail: "maintainer"},
+		{Team: "platform", Action: ActionRemoveMember, Target: "dave"},
+		{Team: "platform-sre", Action: ActionCreateTeam, Detail: "parent platform"},
+		{Team: "platform-sre", Action: ActionAddMember, Target: "carol", Detail: "member"},
+	}
+	if !reflect.DeepEqual(summary.Changes, expected) {
+		t.Errorf("wanted %v, got %v", expected, summary.Changes)
+	}
+}
+
+// TestApplyRepos tests that the reconciler grants missing repositories and fixes wrong permissions
+func TestApplyRepos(t *testing.T) {
+
+	teamsURL := fmt.Sprintf("%s/orgs/%s/teams", groups.DefaultAPIURL, groups.TestOrg)
+	state, err := Parse([]byte(`
+teams:
+  - name: platform
+    repos:
+      - name: HybriStratus/infra
+        permission: push
+      - name: HybriStratus/docs
+      - name: HybriStratus/web
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the mock Client, the team is then addressed by its slug rather than its name
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/platform", http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 1, "name": "platform", "slug": "platform-infra"}`)),
+	})
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/platform-infra/repos", http.Response{
+		StatusCode: http.StatusOK,
+		Body: ConvertBytesToIoReadCloser([]byte(`[
+			{"full_name": "HybriStratus/infra", "permissions": {"pull": true}},
+			{"full_name": "HybriStratus/docs", "permissions": {"pull": true, "triage": true, "push": true}}
+		]`)),
+	})
+	mockClient.Expect(http.MethodPut, teamsURL+"/platform-infra/repos/HybriStratus/infra", mock.JSONBody(`{"permission": "push"}`)).
+		Respond(http.Response{StatusCode: http.StatusNoContent})
+	mockClient.Expect(http.MethodPut, teamsURL+"/platform-infra/repos/HybriStratus/web", mock.JSONBody(`{"permission": "pull"}`)).
+		Respond(http.Response{StatusCode: http.StatusNoContent})
+
+	reconciler := NewReconciler(groups.NewService(mockClient, groups.TestOrg, ""))
+	summary, err := reconciler.Apply(context.Background(), state)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	mockClient.AssertExpectations(t)
+
+	expected := []Change{
+		{Team: "platform", Action: ActionChangeRepoPermission, Target: "HybriStratus/infra", Detail: "push"},
+		{Team: "platform", Action: ActionAddRepo, Target: "HybriStratus/web", Detail: "pull"},
+	}
+	if !reflect.DeepEqual(summary.Changes, expected) {
+		t.Errorf("wanted %v, got %v", expected, summary.Changes)
+	}
+}
+
+// TestApplyPending tests that users pending until they join the organization are not
+// added again on the next runs and are removed once they are no longer desired
+func TestApplyPending(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddOrg(groups.TestOrg)
+	server.AddOrgMember(groups.TestOrg, "alice", "member")
+	server.AddUser("outsider")
+	reconciler := NewReconciler(groups.NewService(net.Client{}, groups.TestOrg, server.URL))
+
+	// Create your table test
+	tests := []struct {
+		name     string
+		state    string
+		expected string
+	}{
+		{
+			name:     "Testing first run",
+			state:    `{"teams": [{"name": "Platform Team", "members": ["alice", "outsider"]}]}`,
+			expected: "create-team Platform Team\nadd-member Platform Team alice (member)\nadd-member Platform Team outsider (member)\n",
+		},
+		{
+			name:     "Testing second run",
+			state:    `{"teams": [{"name": "Platform Team", "members": ["alice", "outsider"]}]}`,
+			expected: "No changes\n",
+		},
+		{
+			name:     "Testing role of a pending user",
+			state:    `{"teams": [{"name": "Platform Team", "members": ["alice"], "maintainers": ["outsider"]}]}`,
+			expected: "change-role Platform Team outsider (maintainer)\n",
+		},
+		{
+			name:     "Testing removal of a pending user",
+			state:    `{"teams": [{"name": "Platform Team", "members": ["alice"]}]}`,
+			expected: "remove-member Platform Team outsider\n",
+		},
+	}
+	// Go through each of the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := Parse([]byte(tt.state))
+			if err != nil {
+				t.Fatal(err)
+			}
+			summary, err := reconciler.Apply(context.Background(), state)
+			if err != nil {
+				t.Fatalf("wanted no error, got %v", err)
+			}
+			if summary.String() != tt.expected {
+				t.Errorf("wanted %q, got %q", tt.expected, summary.String())
+			}
+		})
+	}
+}
+
+// TestApplyPlan tests that the reconciler plans changes without sending them in plan mode
+func TestApplyPlan(t *testing.T) {
+
+	teamsURL := fmt.Sprintf("%s/orgs/%s/teams", groups.DefaultAPIURL, groups.TestOrg)
+	state := &DesiredState{Teams: []TeamSpec{
+		{Name: "sre", Parent: "eng"},
+		{Name: "eng", Members: []string{"bob"}},
+		{Name: "platform", Parent: "eng"},
+	}}
+
+	// Only the reads are answered by the mock Client
+	mockClient := mock.Client{}
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/eng", http.Response{StatusCode: http.StatusNotFound})
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/eng/members", http.Response{StatusCode: http.StatusNotFound})
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/eng/invitations", http.Response{StatusCode: http.StatusNotFound})
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/sre", http.Response{StatusCode: http.StatusNotFound})
+	mockClient.SetResponses(http.MethodGet, teamsURL+"/platform", http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ConvertBytesToIoReadCloser([]byte(`{"id": 3, "name": "platform", "slug": "platform"}`)),
+	})
+	planClient := plan.NewClient(mockClient)
+
+	reconciler := NewReconciler(groups.NewService(planClient, groups.TestOrg, ""))
+	summary, err := reconciler.Apply(context.Background(), state)
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+
+	expected := []Change{
+		{Team: "eng", Action: ActionCreateTeam},
+		{Team: "eng", Action: ActionAddMember, Target: "bob", Detail: "member"},
+		{Team: "sre", Action: ActionCreateTeam, Detail: "parent eng"},
+		{Team: "platform", Action: ActionUpdateTeam, Detail: "parent"},
+	}
+	if !reflect.DeepEqual(summary.Changes, expected) {
+		t.Errorf("wanted %v, got %v", expected, summary.Changes)
+	}
+
+	changes := planClient.Changes()
+	if len(changes) != 3 || changes[0].Method != http.MethodPost || changes[1].URL != teamsURL+"/eng/memberships/bob" ||
+		string(changes[2].Body) != `{"name":"sre"}` {
+		t.Errorf("wanted planned POST, PUT and POST, got %v", changes)
+	}
+}
+
+// TestOrderByParent tests that parents are reconciled first and cycles are rejected
+func TestOrderByParent(t *testing.T) {
+
+	ordered, err := orderByParent([]TeamSpec{{Name: "c", Parent: "b"}, {Name: "b", Parent: "a"}, {Name: "a"}})
+	if err != nil {
+		t.Fatalf("wanted no error, got %v", err)
+	}
+	var names []string
+	for _, spec := range ordered {
+		names = append(names, spec.Name)
+	}
+	if fmt.Sprint(names) != "[a b c]" {
+		t.Errorf("wanted [a b c], got %v", names)
+	}
+
+	_, err = orderByParent([]TeamSpec{{Name: "a", Parent: "b"}, {Name: "b", Parent: "a"}})
+	if err == nil {
+		t.Errorf("wanted a cycle error")
+	}
+}
+
+// Converts an bytes into an io.ReadCloser, which is used as the body of an API call
+func ConvertBytesToIoReadCloser(objectByte []byte) io.ReadCloser {
+	objectReader := bytes.NewReader(objectByte)
+	return ioutil.NopCloser(objectReader)
+}
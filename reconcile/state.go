@@ -0,0 +1,81 @@
+package reconcile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DesiredState lists the teams that should exist in the organization
+type DesiredState struct {
+	Teams []TeamSpec `json:"teams" yaml:"teams"`
+}
+
+// TeamSpec is the desired state of a single team. Empty fields are left as they are
+type TeamSpec struct {
+	Name        string     `json:"name" yaml:"name"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Privacy     string     `json:"privacy,omitempty" yaml:"privacy,omitempty"`
+	Parent      string     `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Maintainers []string   `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+	Members     []string   `json:"members,omitempty" yaml:"members,omitempty"`
+	Repos       []RepoSpec `json:"repos,omitempty" yaml:"repos,omitempty"`
+}
+
+// RepoSpec is a repository the team should have access to
+type RepoSpec struct {
+	// Name is the full name of the repository, owner/repo
+	Name       string `json:"name" yaml:"name"`
+	Permission string `json:"permission,omitempty" yaml:"permission,omitempty"`
+}
+
+// Load reads the desired state from a YAML or JSON file
+func Load(path string) (*DesiredState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error in reading desired state file %s", err.Error())
+	}
+	return Parse(data)
+}
+
+// Parse parses the desired state from YAML or JSON, which is a subset of YAML
+func Parse(data []byte) (*DesiredState, error) {
+	state := &DesiredState{}
+	err := yaml.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("Error in parsing desired state %s", err.Error())
+	}
+	err = state.validate()
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// validate checks that every team is named once, its parent is not itself and its
+// repositories are named owner/repo
+func (state *DesiredState) validate() error {
+	seen := map[string]bool{}
+	for _, spec := range state.Teams {
+		if spec.Name == "" {
+			return fmt.Errorf("Error in desired state, a team has no name")
+		}
+		key := strings.ToLower(spec.Name)
+		if seen[key] {
+			return fmt.Errorf("Error in desired state, team %s is listed twice", spec.Name)
+		}
+		seen[key] = true
+		if strings.EqualFold(spec.Parent, spec.Name) {
+			return fmt.Errorf("Error in desired state, team %s is its own parent", spec.Name)
+		}
+		for _, repo := range spec.Repos {
+			parts := strings.Split(repo.Name, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("Error in desired state, repository %s of team %s is not named owner/repo", repo.Name, spec.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/HybriStratus/test-github-groups/reconcile"
+)
+
+// runSync reconciles the organization with a desired state file
+func runSync(args []string, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("sync")
+	positional, err := parseArgs(fs, opts, args)
+	if err != nil {
+		return usageError(stderr, "%s", err.Error())
+	}
+	if err := checkArgs(positional, "file"); err != nil {
+		return usageError(stderr, "%s", err.Error())
+	}
+
+	state, err := reconcile.Load(positional[0])
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	service, err := opts.service(stderr)
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	summary, applyErr := reconcile.NewReconciler(service).Apply(context.Background(), state)
+
+	// The changes made before a failure are reported as well
+	err = writeOutput(opts.resultWriter(stdout), opts.output, summary, func(tw io.Writer) {
+		fmt.Fprint(tw, summary.String())
+	})
+	if err == nil {
+		err = opts.writePlan(stdout)
+	}
+	if applyErr != nil {
+		return commandError(stderr, applyErr)
+	}
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	return exitOK
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/HybriStratus/test-github-groups/groups"
+)
+
+// runTeam executes the team subcommands
+func runTeam(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return usageError(stderr, "missing team subcommand")
+	}
+
+	fs, opts := newFlagSet("team " + args[0])
+	team := &groups.Team{}
+	if args[0] == "create" || args[0] == "update" || args[0] == "ensure" {
+		fs.StringVar(&team.Description, "description", "", "Description of the team")
+		fs.StringVar(&team.Privacy, "privacy", "", "Privacy of the team, secret or closed")
+		fs.IntVar(&team.ParentTeamID, "parent-id", 0, "ID of the parent team")
+		fs.StringVar(&team.ParentTeamSlug, "parent", "", "Slug of the parent team")
+	}
+	filter := &groups.TeamFilter{}
+	var nameRegexp string
+	var minMembers, maxMembers int
+	if args[0] == "list" {
+		fs.StringVar(&filter.NamePrefix, "name-prefix", "", "Only teams whose name starts with the prefix")
+		fs.StringVar(&nameRegexp, "name-regexp", "", "Only teams whose name matches the regular expression")
+		fs.StringVar(&filter.Privacy, "privacy", "", "Only teams with the privacy, secret or closed")
+		fs.StringVar(&filter.Parent, "parent", "", "Only child teams of the parent")
+		fs.BoolVar(&filter.RootOnly, "root", false, "Only teams without a parent")
+		fs.IntVar(&minMembers, "min-members", -1, "Only teams with at least this many members")
+		fs.IntVar(&maxMembers, "max-members", -1, "Only teams with at most this many members")
+	}
+	positional, err := parseArgs(fs, opts, args[1:])
+	if err != nil {
+		return usageError(stderr, "%s", err.Error())
+	}
+
+	ctx := context.Background()
+	service, err := opts.service(stderr)
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	out := opts.resultWriter(stdout)
+	switch args[0] {
+	case "create", "update":
+		if err := checkArgs(positional, "name"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		team.Name = positional[0]
+		var details *groups.TeamDetails
+		if args[0] == "create" {
+			details, err = service.CreateTeam(ctx, team)
+		} else {
+			details, err = service.UpdateTeam(ctx, team)
+		}
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeTeams(out, opts.output, details, []groups.TeamDetails{*details})
+
+	case "ensure":
+		if err := checkArgs(positional, "name"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		team.Name = positional[0]
+		var details *groups.TeamDetails
+		var result *groups.EnsureResult
+		details, result, err = service.EnsureTeam(ctx, team)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		output := struct {
+			*groups.EnsureResult
+			Team *groups.TeamDetails `json:"team"`
+		}{result, details}
+		err = writeOutput(out, opts.output, output, func(tw io.Writer) {
+			fmt.Fprintln(tw, "ID\tNAME\tSLUG\tACTION\tFIELDS")
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", details.ID, details.Name, details.Slug, result.Action, strings.Join(result.Fields, ","))
+		})
+
+	case "get":
+		if err := checkArgs(positional, "name"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var details *groups.TeamDetails
+		details, err = service.GetTeamDetails(ctx, positional[0])
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeTeams(out, opts.output, details, []groups.TeamDetails{*details})
+
+	case "delete":
+		if err := checkArgs(positional, "name"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		err = service.DeleteTeam(ctx, positional[0])
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeMessage(out, opts.output, "deleted", map[string]string{"team": positional[0]},
+			fmt.Sprintf("Deleted team %s", positional[0]))
+
+	case "list":
+		if err := checkArgs(positional); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		if nameRegexp != "" {
+			filter.NameRegexp, err = regexp.Compile(nameRegexp)
+			if err != nil {
+				return usageError(stderr, "--name-regexp %s", err.Error())
+			}
+		}
+		if minMembers >= 0 {
+			filter.MinMembers = groups.Int(minMembers)
+		}
+		if maxMembers >= 0 {
+			filter.MaxMembers = groups.Int(maxMembers)
+		}
+		var teams []groups.TeamDetails
+		teams, err = service.SearchTeams(ctx, filter, nil)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeTeams(out, opts.output, teams, teams)
+
+	case "tree":
+		if err := checkArgs(positional); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var roots []*groups.TeamNode
+		roots, err = service.GetTeamTree(ctx)
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeTeamTree(out, opts.output, roots)
+
+	case "move":
+		// Without a parent the team becomes a root team
+		if len(positional) == 1 {
+			positional = append(positional, "")
+		}
+		if err := checkArgs(positional, "name", "parent"); err != nil {
+			return usageError(stderr, "%s", err.Error())
+		}
+		var details *groups.TeamDetails
+		details, err = service.MoveTeam(ctx, positional[0], positional[1])
+		if err != nil {
+			return commandError(stderr, err)
+		}
+		err = writeTeams(out, opts.output, details, []groups.TeamDetails{*details})
+
+	default:
+		return usageError(stderr, "unknown team subcommand %q", args[0])
+	}
+
+	if err == nil {
+		err = opts.writePlan(stdout)
+	}
+	if err != nil {
+		return commandError(stderr, err)
+	}
+	return exitOK
+}